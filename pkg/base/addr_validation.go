@@ -86,6 +86,72 @@ func (cfg *Config) ValidateAddrs(ctx context.Context) error {
 	return nil
 }
 
+// ValidateListenerPorts checks that no two listeners configured in
+// the Config would bind the same host:port. A wildcard host (empty,
+// 0.0.0.0 or ::) conflicts with any host on the same port. Port 0
+// requests an ephemeral port and never conflicts. The SQL address is
+// only considered when SplitListenSQL is set; otherwise SQL is served
+// on the RPC listener by design.
+//
+// Hosts are compared textually; ValidateAddrs should be called first
+// so that host names are resolved to IP addresses.
+func (cfg *Config) ValidateListenerPorts() error {
+	type listener struct {
+		name       string
+		host, port string
+	}
+	addrs := []struct{ name, addr string }{
+		{"RPC", cfg.Addr},
+		{"HTTP", cfg.HTTPAddr},
+	}
+	if cfg.SplitListenSQL {
+		addrs = append(addrs, struct{ name, addr string }{"SQL", cfg.SQLAddr})
+	}
+
+	// Group the listeners by port, then compare hosts within a group.
+	byPort := make(map[string][]listener)
+	var ports []string
+	for _, a := range addrs {
+		if a.addr == "" {
+			continue
+		}
+		host, port, err := net.SplitHostPort(a.addr)
+		if err != nil {
+			return errors.Wrapf(err, "invalid %s listen address", a.name)
+		}
+		if port == "" || port == "0" {
+			continue
+		}
+		if _, ok := byPort[port]; !ok {
+			ports = append(ports, port)
+		}
+		byPort[port] = append(byPort[port], listener{name: a.name, host: host, port: port})
+	}
+	for _, port := range ports {
+		ls := byPort[port]
+		for i := range ls {
+			for j := i + 1; j < len(ls); j++ {
+				a, b := ls[i], ls[j]
+				if a.host == b.host || isWildcardHost(a.host) || isWildcardHost(b.host) {
+					return errors.Errorf("%s listen address %s conflicts with %s listen address %s",
+						b.name, net.JoinHostPort(b.host, b.port), a.name, net.JoinHostPort(a.host, a.port))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isWildcardHost returns true if listening on host binds all
+// interfaces.
+func isWildcardHost(host string) bool {
+	if host == "" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsUnspecified()
+}
+
 // validateAdvertiseAddr validates and normalizes an address suitable
 // for use in gossiping - for use by other nodes. This ensures
 // that if the "host" part is empty, it gets filled in with
@@ -196,3 +196,51 @@ func TestValidateAddrs(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateListenerPorts(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testData := []struct {
+		rpc, http, sql string
+		split          bool
+		expectedErr    string
+	}{
+		{"127.0.0.1:26257", "127.0.0.1:8080", "127.0.0.1:26258", true, ""},
+		// SQL shares the RPC listener unless split.
+		{"127.0.0.1:26257", "127.0.0.1:8080", "127.0.0.1:26257", false, ""},
+		{"127.0.0.1:26257", "127.0.0.1:8080", "127.0.0.1:8080", true,
+			"SQL listen address 127.0.0.1:8080 conflicts with HTTP listen address 127.0.0.1:8080"},
+		{"127.0.0.1:26257", "127.0.0.1:8080", "127.0.0.1:26257", true,
+			"SQL listen address 127.0.0.1:26257 conflicts with RPC listen address 127.0.0.1:26257"},
+		// Wildcard hosts conflict with specific hosts on the same port.
+		{":26257", "127.0.0.1:26257", "", false,
+			"HTTP listen address 127.0.0.1:26257 conflicts with RPC listen address :26257"},
+		{"127.0.0.1:26257", "0.0.0.0:8080", "127.0.0.2:8080", true,
+			"SQL listen address 127.0.0.2:8080 conflicts with HTTP listen address 0.0.0.0:8080"},
+		// Distinct specific hosts may share a port.
+		{"127.0.0.1:26257", "127.0.0.2:26257", "", false, ""},
+		// Ephemeral ports never conflict.
+		{"127.0.0.1:0", "127.0.0.1:0", "127.0.0.1:0", true, ""},
+	}
+
+	for i, test := range testData {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			cfg := base.Config{
+				Addr:           test.rpc,
+				HTTPAddr:       test.http,
+				SQLAddr:        test.sql,
+				SplitListenSQL: test.split,
+			}
+			err := cfg.ValidateListenerPorts()
+			if test.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("expected success, got %v", err)
+				}
+				return
+			}
+			if !testutils.IsError(err, test.expectedErr) {
+				t.Fatalf("expected error %q, got %v", test.expectedErr, err)
+			}
+		})
+	}
+}
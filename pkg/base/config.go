@@ -333,11 +333,22 @@ type Config struct {
 	// the default case, password authentication is still mandatory.
 	AcceptSQLWithoutTLS bool
 
+	// AcceptInsecureExposure, when set, allows an insecure server to listen
+	// on or advertise hosts other than loopback hosts, i.e. to be reachable
+	// from the network without any authentication. Addresses with no host,
+	// like the default listen addresses, are always allowed.
+	AcceptInsecureExposure bool
+
 	// SSLCAKey is used to sign new certs.
 	SSLCAKey string
 	// SSLCertsDir is the path to the certificate/key directory.
 	SSLCertsDir string
 
+	// MinTLSVersion is the minimum TLS version, "1.2" or "1.3", accepted by
+	// the SQL, RPC and HTTP listeners and used by outgoing connections. It
+	// defaults to 1.2 when empty.
+	MinTLSVersion string
+
 	// User running this process. It could be the user under which
 	// the server is running or the user passed in client calls.
 	User username.SQLUsername
@@ -394,12 +405,35 @@ type Config struct {
 	// This is auto-populated from SQLAddr if it initially ends with '.0'.
 	SocketFile string
 
+	// MaxConnsPerIP, if positive, limits the number of SQL connections that
+	// may be open simultaneously from a single client IP address over the
+	// network. New connections beyond the limit are rejected. Zero means
+	// unlimited.
+	MaxConnsPerIP int
+
+	// MaxNewConnsPerSecond, if positive, limits the rate at which the SQL
+	// listener accepts new connections over the network, with bursts of up to
+	// that many connections. Connections beyond the rate are delayed. Zero
+	// means unlimited.
+	MaxNewConnsPerSecond int
+
+	// DrainAllowedUsers are the SQL users whose sessions are kept open when
+	// the server drains, e.g. for system connections which must persist
+	// throughout a rolling upgrade. A session is matched against the list
+	// using the user it authenticated as. Sessions of other users are drained
+	// as usual. New connections are rejected during a drain regardless.
+	DrainAllowedUsers []string
+
 	// HTTPAddr is the configured HTTP listen address.
 	HTTPAddr string
 
 	// DisableTLSForHTTP, if set, disables TLS for the HTTP listener.
 	DisableTLSForHTTP bool
 
+	// HTTPPathPrefix, if set, is the path under which a reverse proxy serves
+	// the HTTP endpoints, e.g. /crdb/. It is included in AdminURL.
+	HTTPPathPrefix string
+
 	// HTTPAdvertiseAddrH contains the advertised HTTP address.
 	// This is computed from HTTPAddr if specified otherwise Addr.
 	//
@@ -482,11 +516,15 @@ func (cfg *Config) InitDefaults() {
 	cfg.AdvertiseAddr = cfg.Addr
 	cfg.HTTPAddr = defaultHTTPAddr
 	cfg.DisableTLSForHTTP = false
+	cfg.HTTPPathPrefix = ""
 	cfg.HTTPAdvertiseAddr = ""
 	cfg.SplitListenSQL = false
 	cfg.SQLAddr = defaultSQLAddr
 	cfg.SQLAdvertiseAddr = cfg.SQLAddr
 	cfg.SocketFile = ""
+	cfg.MaxConnsPerIP = 0
+	cfg.MaxNewConnsPerSecond = 0
+	cfg.DrainAllowedUsers = nil
 	cfg.SSLCertsDir = DefaultCertsDirectory
 	cfg.RPCHeartbeatInterval = PingInterval
 	cfg.RPCHeartbeatTimeout = DefaultRPCHeartbeatTimeout
@@ -494,6 +532,7 @@ func (cfg *Config) InitDefaults() {
 	cfg.DisableClusterNameVerification = false
 	cfg.ClockDevicePath = ""
 	cfg.AcceptSQLWithoutTLS = false
+	cfg.AcceptInsecureExposure = false
 	cfg.ApplicationInternalRPCPortMin = 0
 	cfg.ApplicationInternalRPCPortMax = 0
 }
@@ -507,12 +546,17 @@ func (cfg *Config) HTTPRequestScheme() string {
 	return httpsScheme
 }
 
-// AdminURL returns the URL for the admin UI.
+// AdminURL returns the URL for the admin UI. Its path is HTTPPathPrefix,
+// with a leading and a trailing slash, if one is set.
 func (cfg *Config) AdminURL() *url.URL {
-	return &url.URL{
+	u := &url.URL{
 		Scheme: cfg.HTTPRequestScheme(),
 		Host:   cfg.HTTPAdvertiseAddr,
 	}
+	if prefix := strings.Trim(cfg.HTTPPathPrefix, "/"); prefix != "" {
+		u.Path = "/" + prefix + "/"
+	}
+	return u
 }
 
 // RaftConfig holds raft tuning parameters.
@@ -158,6 +158,28 @@ func TestRaftMaxInflightBytes(t *testing.T) {
 	}
 }
 
+func TestAdminURLPathPrefix(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	for _, tc := range []struct {
+		prefix, want string
+	}{
+		{"", "http://db.example.com:8080"},
+		{"/", "http://db.example.com:8080"},
+		{"crdb", "http://db.example.com:8080/crdb/"},
+		{"/crdb", "http://db.example.com:8080/crdb/"},
+		{"crdb/", "http://db.example.com:8080/crdb/"},
+		{"/crdb/", "http://db.example.com:8080/crdb/"},
+		{"/proxy/crdb/", "http://db.example.com:8080/proxy/crdb/"},
+	} {
+		t.Run(tc.prefix, func(t *testing.T) {
+			cfg := base.Config{Insecure: true}
+			cfg.HTTPAdvertiseAddr = "db.example.com:8080"
+			cfg.HTTPPathPrefix = tc.prefix
+			require.Equal(t, tc.want, cfg.AdminURL().String())
+		})
+	}
+}
+
 func TestWALFailoverConfigRoundtrip(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
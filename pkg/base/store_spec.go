@@ -83,19 +83,12 @@ func NewSizeSpec(
 	field redact.SafeString, value string, bytesRange *intInterval, percentRange *floatInterval,
 ) (SizeSpec, error) {
 	var size SizeSpec
-	if fractionRegex.MatchString(value) {
-		percentFactor := 100.0
-		factorValue := value
-		if value[len(value)-1] == '%' {
-			percentFactor = 1.0
-			factorValue = value[:len(value)-1]
-		}
-		var err error
-		size.Percent, err = strconv.ParseFloat(factorValue, 64)
-		size.Percent *= percentFactor
-		if err != nil {
-			return SizeSpec{}, errors.Wrapf(err, "could not parse %s size (%s)", field, value)
-		}
+	bs, err := humanizeutil.ParseByteSize(value)
+	if err != nil {
+		return SizeSpec{}, errors.Wrapf(err, "could not parse %s size (%s)", field, value)
+	}
+	if bs.IsPercent {
+		size.Percent = bs.Percent
 		if percentRange != nil {
 			if (percentRange.min != nil && size.Percent < *percentRange.min) ||
 				(percentRange.max != nil && size.Percent > *percentRange.max) {
@@ -109,11 +102,7 @@ func NewSizeSpec(
 			}
 		}
 	} else {
-		var err error
-		size.InBytes, err = humanizeutil.ParseBytes(value)
-		if err != nil {
-			return SizeSpec{}, errors.Wrapf(err, "could not parse %s size (%s)", field, value)
-		}
+		size.InBytes = bs.Bytes
 		if bytesRange != nil {
 			if bytesRange.min != nil && size.InBytes < *bytesRange.min {
 				return SizeSpec{}, errors.Newf("%s size (%s) must be larger than %s",
@@ -228,6 +217,31 @@ type StoreSpec struct {
 	EncryptionOptions []byte
 	// ProvisionedRateSpec is optional.
 	ProvisionedRateSpec ProvisionedRateSpec
+	// MaxWALSize, if positive, bounds the size of the WAL that has not been
+	// flushed yet, and hence the amount of WAL replayed on recovery.
+	MaxWALSize int64
+	// BytesPerSync and WALBytesPerSync, if positive, are the number of bytes
+	// written to an sstable or a WAL, respectively, after which the engine asks
+	// the OS to write them out in the background, so that dirty buffers are
+	// flushed incrementally rather than in large bursts.
+	BytesPerSync    int64
+	WALBytesPerSync int64
+	// MaxBackgroundJobs, if positive, bounds the number of concurrent
+	// background jobs, flushes and compactions combined, of the store.
+	MaxBackgroundJobs int
+	// AdmissionIOTokenRate, if positive, bounds the rate in bytes/s at which
+	// admission control lets work write to the store. By default, the rate is
+	// only limited by what the store's LSM can absorb.
+	AdmissionIOTokenRate int64
+	// L0CompactionThreshold, if positive, is the L0 read amplification at
+	// which the store starts compacting L0 into the lower levels.
+	L0CompactionThreshold int
+	// SampleThroughput, if set, enables the periodic sampling of the store's
+	// write throughput into the storage.write-throughput metric.
+	SampleThroughput bool
+	// CacheSize, if positive, gives the store a block cache of its own of
+	// that size, instead of sharing the node-wide cache with the other stores.
+	CacheSize int64
 }
 
 // String returns a fully parsable version of the store spec.
@@ -235,7 +249,7 @@ func (ss StoreSpec) String() string {
 	// TODO(jackson): Implement redact.SafeFormatter
 	var buffer bytes.Buffer
 	if len(ss.Path) != 0 {
-		fmt.Fprintf(&buffer, "path=%s,", ss.Path)
+		fmt.Fprintf(&buffer, "path=%s,", storePathEscaper.Replace(ss.Path))
 	}
 	if ss.InMemory {
 		fmt.Fprint(&buffer, "type=mem,")
@@ -274,6 +288,30 @@ func (ss StoreSpec) String() string {
 		fmt.Fprintf(&buffer, "provisioned-rate=bandwidth=%s/s,",
 			humanizeutil.IBytes(ss.ProvisionedRateSpec.ProvisionedBandwidth))
 	}
+	if ss.MaxWALSize > 0 {
+		fmt.Fprintf(&buffer, "max-wal-size=%s,", humanizeutil.IBytes(ss.MaxWALSize))
+	}
+	if ss.BytesPerSync > 0 {
+		fmt.Fprintf(&buffer, "bytes-per-sync=%s,", humanizeutil.IBytes(ss.BytesPerSync))
+	}
+	if ss.WALBytesPerSync > 0 {
+		fmt.Fprintf(&buffer, "wal-bytes-per-sync=%s,", humanizeutil.IBytes(ss.WALBytesPerSync))
+	}
+	if ss.MaxBackgroundJobs > 0 {
+		fmt.Fprintf(&buffer, "max-background-jobs=%d,", ss.MaxBackgroundJobs)
+	}
+	if ss.AdmissionIOTokenRate > 0 {
+		fmt.Fprintf(&buffer, "admission-io-tokens=%s/s,", humanizeutil.IBytes(ss.AdmissionIOTokenRate))
+	}
+	if ss.L0CompactionThreshold > 0 {
+		fmt.Fprintf(&buffer, "l0-compaction-threshold=%d,", ss.L0CompactionThreshold)
+	}
+	if ss.SampleThroughput {
+		fmt.Fprint(&buffer, "sample-throughput=true,")
+	}
+	if ss.CacheSize > 0 {
+		fmt.Fprintf(&buffer, "cache-size=%s,", humanizeutil.IBytes(ss.CacheSize))
+	}
 	// Trim the extra comma from the end if it exists.
 	if l := buffer.Len(); l > 0 {
 		buffer.Truncate(l - 1)
@@ -286,25 +324,16 @@ func (ss StoreSpec) IsEncrypted() bool {
 	return len(ss.EncryptionOptions) > 0
 }
 
-// fractionRegex is the regular expression that recognizes whether
-// the specified size is a fraction of the total available space.
-// Proportional sizes can be expressed as fractional numbers, either
-// in absolute value or with a trailing "%" sign. A fractional number
-// without a trailing "%" must be recognized by the presence of a
-// decimal separator; numbers without decimal separators are plain
-// sizes in bytes (separate case in the parsing).
-// The first part of the regexp matches NNN.[MMM]; the second part
-// [NNN].MMM, and the last part matches explicit percentages with or
-// without a decimal separator.
-// Values smaller than 1% and 100% are rejected after parsing using
-// a separate check.
-var fractionRegex = regexp.MustCompile(`^([-]?([0-9]+\.[0-9]*|[0-9]*\.[0-9]+|[0-9]+(\.[0-9]*)?%))$`)
-
 // NewStoreSpec parses the string passed into a --store flag and returns a
 // StoreSpec if it is correctly parsed.
 // There are five possible fields that can be passed in, comma separated:
 //   - path=xxx The directory in which the rocks db instance should be
-//     located, required unless using an in memory storage.
+//     located, required unless using an in memory storage. Commas, colons and
+//     backslashes within the path may be escaped with a backslash, as in \,
+//     \: and \\.
+//     References to environment variables must be written as ${VAR}, and
+//     are expanded; $${ stands for a literal ${. Other dollar signs are
+//     kept as is.
 //   - type=mem This specifies that the store is an in memory storage instead of
 //     an on disk one. mem is currently the only other type available.
 //   - size=xxx The optional maximum size of the storage. This can be in one of a
@@ -319,16 +348,39 @@ var fractionRegex = regexp.MustCompile(`^([-]?([0-9]+\.[0-9]*|[0-9]*\.[0-9]+|[0-
 //   - provisioned-rate=bandwidth=<bandwidth-bytes/s> The provisioned-rate can be
 //     used for admission control for operations on the store and if unspecified,
 //     a cluster setting (kvadmission.store.provisioned_bandwidth) will be used.
+//   - max-wal-size=xxx The optional bound on the size of the WAL that has not
+//     been flushed, which the store replays on recovery. It must be larger
+//     than the memtable size.
+//   - bytes-per-sync=xxx and wal-bytes-per-sync=xxx The optional amounts of
+//     sstable and WAL data, respectively, written between background syncs.
+//     Zero keeps the engine defaults.
+//   - max-background-jobs=xxx The optional bound on the number of concurrent
+//     flushes and compactions of the store. By default, it is derived from the
+//     number of CPUs per store.
+//   - admission-io-tokens=<bytes/s> The optional bound on the rate at which
+//     admission control admits writes to the store.
+//   - l0-compaction-threshold=xxx The optional L0 read amplification at which
+//     L0 compactions are triggered. It must be below the L0 read amplification
+//     at which writes stop.
+//   - sample-throughput=<bool> Whether to periodically sample the write
+//     throughput of the store into a metric. Defaults to false.
+//   - cache-size=xxx The optional size of a block cache dedicated to the store.
+//     By default, on-disk stores share the node-wide cache.
+//   - preset=xxx A bundle of the options above and of Pebble options tuned for
+//     a workload: write_optimized, read_optimized or balanced. See
+//     storePresets. Options given explicitly override those of the preset.
 //
-// Note that commas are forbidden within any field name or value.
+// Note that commas are forbidden within any field name or value other than
+// the path.
 func NewStoreSpec(value string) (StoreSpec, error) {
 	const pathField = "path"
 	if len(value) == 0 {
 		return StoreSpec{}, fmt.Errorf("no value specified")
 	}
 	var ss StoreSpec
+	var preset string
 	used := make(map[string]struct{})
-	for _, split := range strings.Split(value, ",") {
+	for _, split := range splitStoreSpecFields(value) {
 		if len(split) == 0 {
 			continue
 		}
@@ -356,7 +408,11 @@ func NewStoreSpec(value string) (StoreSpec, error) {
 
 		switch field {
 		case pathField:
-			ss.Path = value
+			path, err := expandStorePath(storePathUnescaper.Replace(value))
+			if err != nil {
+				return StoreSpec{}, err
+			}
+			ss.Path = path
 		case "size":
 			var err error
 			var minBytesAllowed int64 = MinimumStoreSize
@@ -448,11 +504,86 @@ func NewStoreSpec(value string) (StoreSpec, error) {
 				return StoreSpec{}, err
 			}
 			ss.ProvisionedRateSpec = rateSpec
+		case "max-wal-size":
+			size, err := humanizeutil.ParseBytes(value)
+			if err != nil {
+				return StoreSpec{}, errors.Wrapf(err, "could not parse max-wal-size (%s)", value)
+			}
+			if size <= 0 {
+				return StoreSpec{}, fmt.Errorf("max-wal-size must be positive, got %s", value)
+			}
+			ss.MaxWALSize = size
+		case "bytes-per-sync", "wal-bytes-per-sync":
+			size, err := humanizeutil.ParseBytes(value)
+			if err != nil {
+				return StoreSpec{}, errors.Wrapf(err, "could not parse %s (%s)", field, value)
+			}
+			if size < 0 {
+				return StoreSpec{}, fmt.Errorf("%s must be non-negative, got %s", field, value)
+			}
+			if field == "bytes-per-sync" {
+				ss.BytesPerSync = size
+			} else {
+				ss.WALBytesPerSync = size
+			}
+		case "max-background-jobs":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return StoreSpec{}, errors.Wrapf(err, "could not parse max-background-jobs (%s)", value)
+			}
+			if n < 1 {
+				return StoreSpec{}, fmt.Errorf("max-background-jobs must be at least 1, got %s", value)
+			}
+			ss.MaxBackgroundJobs = n
+		case "admission-io-tokens":
+			if len(value) <= 2 || value[len(value)-2:] != "/s" {
+				return StoreSpec{}, fmt.Errorf("admission-io-tokens value %s does not end in /s", value)
+			}
+			rate, err := humanizeutil.ParseBytes(value[:len(value)-2])
+			if err != nil {
+				return StoreSpec{}, errors.Wrapf(err, "could not parse admission-io-tokens (%s)", value)
+			}
+			if rate <= 0 {
+				return StoreSpec{}, fmt.Errorf("admission-io-tokens must be positive, got %s", value)
+			}
+			ss.AdmissionIOTokenRate = rate
+		case "l0-compaction-threshold":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return StoreSpec{}, errors.Wrapf(err, "could not parse l0-compaction-threshold (%s)", value)
+			}
+			if n < 1 {
+				return StoreSpec{}, fmt.Errorf("l0-compaction-threshold must be positive, got %s", value)
+			}
+			ss.L0CompactionThreshold = n
+		case "sample-throughput":
+			sample, err := strconv.ParseBool(value)
+			if err != nil {
+				return StoreSpec{}, errors.Wrapf(err, "could not parse sample-throughput (%s)", value)
+			}
+			ss.SampleThroughput = sample
+		case "preset":
+			if _, ok := storePresets[value]; !ok {
+				return StoreSpec{}, fmt.Errorf("%s is not a valid store preset", value)
+			}
+			preset = value
+		case "cache-size":
+			size, err := humanizeutil.ParseBytes(value)
+			if err != nil {
+				return StoreSpec{}, errors.Wrapf(err, "could not parse cache-size (%s)", value)
+			}
+			if size <= 0 {
+				return StoreSpec{}, fmt.Errorf("cache-size must be positive, got %s", value)
+			}
+			ss.CacheSize = size
 
 		default:
 			return StoreSpec{}, fmt.Errorf("%s is not a valid store field", field)
 		}
 	}
+	if preset != "" {
+		storePresets[preset].apply(&ss, used)
+	}
 	if ss.InMemory {
 		// Only in memory stores don't need a path and require a size.
 		if ss.Path != "" {
@@ -470,6 +601,131 @@ func NewStoreSpec(value string) (StoreSpec, error) {
 	return ss, nil
 }
 
+// storePreset is a bundle of store options selected by the preset field of a
+// store spec.
+type storePreset struct {
+	l0CompactionThreshold int
+	bytesPerSync          int64
+	// pebbleOptions are in the newline-delimited Pebble OPTIONS format.
+	pebbleOptions string
+}
+
+// storePresets are the valid values of the preset field of a store spec. The
+// compaction style is not part of the presets, as Pebble only implements
+// leveled compactions.
+var storePresets = map[string]storePreset{
+	// write_optimized delays L0 compactions and allows more memtables to queue
+	// up for flushing, trading read amplification for fewer write stalls. It
+	// also syncs sstables less often.
+	"write_optimized": {
+		l0CompactionThreshold: 4,
+		bytesPerSync:          1 << 20, // 1 MiB
+		pebbleOptions:         "[Options]\nmem_table_stop_writes_threshold=6",
+	},
+	// read_optimized compacts L0 eagerly and compresses the bottommost level,
+	// which holds most of the data, with ZSTD so that more of it fits in the
+	// block cache.
+	"read_optimized": {
+		l0CompactionThreshold: 2,
+		pebbleOptions:         "[Level \"6\"]\ncompression=ZSTD",
+	},
+	// balanced keeps the engine defaults.
+	"balanced": {},
+}
+
+// apply sets the options of the preset in ss, except for those that are in
+// used, i.e. that were given explicitly. Pebble options given explicitly are
+// applied after those of the preset, so that they override them key by key.
+func (p storePreset) apply(ss *StoreSpec, used map[string]struct{}) {
+	if _, ok := used["l0-compaction-threshold"]; !ok && p.l0CompactionThreshold > 0 {
+		ss.L0CompactionThreshold = p.l0CompactionThreshold
+	}
+	if _, ok := used["bytes-per-sync"]; !ok && p.bytesPerSync > 0 {
+		ss.BytesPerSync = p.bytesPerSync
+	}
+	if p.pebbleOptions != "" {
+		if ss.PebbleOptions == "" {
+			ss.PebbleOptions = p.pebbleOptions
+		} else {
+			ss.PebbleOptions = p.pebbleOptions + "\n" + ss.PebbleOptions
+		}
+	}
+}
+
+// storePathEscaper and storePathUnescaper convert between store paths and
+// the escaped form used in store specs. Backslashes are escaped too, so that
+// a path ending with a backslash, or containing one followed by a comma or a
+// colon, survives the round trip. Each replacer makes a single pass over its
+// input, so an escaped backslash is never mistaken for the start of another
+// escape. A literal ${ is escaped as $${, which expandStorePath turns back
+// into ${ after unescaping.
+var (
+	storePathEscaper   = strings.NewReplacer(`\`, `\\`, `,`, `\,`, `:`, `\:`, `${`, `$${`)
+	storePathUnescaper = strings.NewReplacer(`\\`, `\`, `\,`, `,`, `\:`, `:`)
+)
+
+// envVarNameRE matches the names of the environment variables which store
+// paths may reference.
+var envVarNameRE = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// expandStorePath expands the ${VAR} references in a store path to the values
+// of the environment variables, and $${ to a literal ${. Only the braced form
+// is expanded, so that existing paths containing a $ keep their meaning. It
+// fails on a malformed reference or an unset variable, rather than opening a
+// store at a partial path.
+func expandStorePath(path string) (string, error) {
+	var buf strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] != '$' {
+			buf.WriteByte(path[i])
+			continue
+		}
+		if strings.HasPrefix(path[i+1:], "${") {
+			buf.WriteString("${")
+			i += 2
+			continue
+		}
+		if !strings.HasPrefix(path[i+1:], "{") {
+			buf.WriteByte('$')
+			continue
+		}
+		end := strings.IndexByte(path[i+2:], '}')
+		if end < 0 {
+			return "", errors.Errorf("store path %s has an unterminated ${ reference", path)
+		}
+		name := path[i+2 : i+2+end]
+		if !envVarNameRE.MatchString(name) {
+			return "", errors.Errorf("store path %s references an invalid environment variable name %q",
+				path, name)
+		}
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", errors.Errorf("store path %s references the unset environment variable %s",
+				path, name)
+		}
+		buf.WriteString(value)
+		i += 2 + end
+	}
+	return buf.String(), nil
+}
+
+// splitStoreSpecFields splits a store spec on the commas that are not escaped
+// by a backslash. The escapes are left in place.
+func splitStoreSpecFields(value string) []string {
+	var fields []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case ',':
+			fields = append(fields, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(fields, value[start:])
+}
+
 // StoreSpecList contains a slice of StoreSpecs that implements pflag's value
 // interface.
 type StoreSpecList struct {
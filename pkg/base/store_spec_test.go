@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/errors"
 	"github.com/stretchr/testify/require"
@@ -69,6 +70,10 @@ target_file_size=2097152`
 		{"path=", "no value specified for path", StoreSpec{}},
 		{"path=/mnt/hda1,path=/mnt/hda2", "path field was used twice in store definition", StoreSpec{}},
 		{"/mnt/hda1,path=/mnt/hda2", "path field was used twice in store definition", StoreSpec{}},
+		{`path=/mnt/weird\,dir`, "", StoreSpec{Path: "/mnt/weird,dir"}},
+		{`/mnt/weird\,dir,attrs=ssd`, "", StoreSpec{Path: "/mnt/weird,dir", Attributes: roachpb.Attributes{Attrs: []string{"ssd"}}}},
+		{`path=/mnt/a\:b\,c,size=20GiB`, "", StoreSpec{Path: "/mnt/a:b,c", Size: SizeSpec{InBytes: 21474836480}}},
+		{`path=/mnt/a:b`, "", StoreSpec{Path: "/mnt/a:b"}},
 
 		// attributes
 		{"path=/mnt/hda1,attrs=ssd", "", StoreSpec{
@@ -157,6 +162,63 @@ target_file_size=2097152`
 		{"path=/mnt/hda1,provisioned-rate=200MiB/s", "provisioned-rate field has invalid value 200MiB/s", StoreSpec{}},
 		{"path=/mnt/hda1,provisioned-rate=bandwidth=0B/s", "provisioned-rate field is trying to set bandwidth to 0", StoreSpec{}},
 
+		// max WAL size
+		{"path=/mnt/hda1,max-wal-size=512MiB", "", StoreSpec{Path: "/mnt/hda1", MaxWALSize: 512 << 20}},
+		{"path=/mnt/hda1,max-wal-size=1GB", "", StoreSpec{Path: "/mnt/hda1", MaxWALSize: 1000000000}},
+		{"type=mem,size=1GiB,max-wal-size=256MiB", "", StoreSpec{Size: SizeSpec{InBytes: 1 << 30}, InMemory: true, MaxWALSize: 256 << 20}},
+		{"path=/mnt/hda1,max-wal-size=0", "max-wal-size must be positive, got 0", StoreSpec{}},
+		{"path=/mnt/hda1,max-wal-size=-1GiB", "max-wal-size must be positive, got -1GiB", StoreSpec{}},
+		{"path=/mnt/hda1,max-wal-size=abc", "could not parse max-wal-size (abc): strconv.ParseFloat: parsing \"\": invalid syntax", StoreSpec{}},
+
+		// bytes per sync
+		{"path=/mnt/hda1,bytes-per-sync=1MiB", "", StoreSpec{Path: "/mnt/hda1", BytesPerSync: 1 << 20}},
+		{"path=/mnt/hda1,wal-bytes-per-sync=512KiB", "", StoreSpec{Path: "/mnt/hda1", WALBytesPerSync: 512 << 10}},
+		{"path=/mnt/hda1,bytes-per-sync=2MiB,wal-bytes-per-sync=1MiB", "", StoreSpec{Path: "/mnt/hda1", BytesPerSync: 2 << 20, WALBytesPerSync: 1 << 20}},
+		{"path=/mnt/hda1,bytes-per-sync=0", "", StoreSpec{Path: "/mnt/hda1"}},
+		{"path=/mnt/hda1,bytes-per-sync=-1MiB", "bytes-per-sync must be non-negative, got -1MiB", StoreSpec{}},
+		{"path=/mnt/hda1,wal-bytes-per-sync=-1", "wal-bytes-per-sync must be non-negative, got -1", StoreSpec{}},
+		{"path=/mnt/hda1,wal-bytes-per-sync=abc", "could not parse wal-bytes-per-sync (abc): strconv.ParseFloat: parsing \"\": invalid syntax", StoreSpec{}},
+
+		// max background jobs
+		{"path=/mnt/hda1,max-background-jobs=4", "", StoreSpec{Path: "/mnt/hda1", MaxBackgroundJobs: 4}},
+		{"path=/mnt/hda1,max-background-jobs=1", "", StoreSpec{Path: "/mnt/hda1", MaxBackgroundJobs: 1}},
+		{"path=/mnt/hda1,max-background-jobs=0", "max-background-jobs must be at least 1, got 0", StoreSpec{}},
+		{"path=/mnt/hda1,max-background-jobs=-2", "max-background-jobs must be at least 1, got -2", StoreSpec{}},
+		{"path=/mnt/hda1,max-background-jobs=four", "could not parse max-background-jobs (four): strconv.Atoi: parsing \"four\": invalid syntax", StoreSpec{}},
+
+		// admission IO tokens
+		{"path=/mnt/hda1,admission-io-tokens=100MiB/s", "", StoreSpec{Path: "/mnt/hda1", AdmissionIOTokenRate: 100 << 20}},
+		{"path=/mnt/hda1,admission-io-tokens=100MiB", "admission-io-tokens value 100MiB does not end in /s", StoreSpec{}},
+		{"path=/mnt/hda1,admission-io-tokens=0/s", "admission-io-tokens must be positive, got 0/s", StoreSpec{}},
+		{"path=/mnt/hda1,admission-io-tokens=-1MiB/s", "admission-io-tokens must be positive, got -1MiB/s", StoreSpec{}},
+
+		// L0 compaction threshold
+		{"path=/mnt/hda1,l0-compaction-threshold=4", "", StoreSpec{Path: "/mnt/hda1", L0CompactionThreshold: 4}},
+		{"path=/mnt/hda1,l0-compaction-threshold=0", "l0-compaction-threshold must be positive, got 0", StoreSpec{}},
+		{"path=/mnt/hda1,l0-compaction-threshold=x", "could not parse l0-compaction-threshold (x): strconv.Atoi: parsing \"x\": invalid syntax", StoreSpec{}},
+
+		// Throughput sampling
+		{"path=/mnt/hda1,sample-throughput=true", "", StoreSpec{Path: "/mnt/hda1", SampleThroughput: true}},
+		{"path=/mnt/hda1,sample-throughput=false", "", StoreSpec{Path: "/mnt/hda1"}},
+		{"path=/mnt/hda1,sample-throughput=x", "could not parse sample-throughput (x): strconv.ParseBool: parsing \"x\": invalid syntax", StoreSpec{}},
+
+		// Per-store cache
+		{"path=/mnt/hda1,cache-size=2GiB", "", StoreSpec{Path: "/mnt/hda1", CacheSize: 2 << 30}},
+		{"path=/mnt/hda1,cache-size=0", "cache-size must be positive, got 0", StoreSpec{}},
+		{"path=/mnt/hda1,cache-size=abc", "could not parse cache-size (abc): strconv.ParseFloat: parsing \"\": invalid syntax", StoreSpec{}},
+
+		// Presets
+		{"path=/mnt/hda1,preset=write_optimized", "", StoreSpec{Path: "/mnt/hda1",
+			L0CompactionThreshold: 4, BytesPerSync: 1 << 20,
+			PebbleOptions: "[Options]\nmem_table_stop_writes_threshold=6"}},
+		{"path=/mnt/hda1,preset=write_optimized,l0-compaction-threshold=8,pebble=[Options] mem_table_stop_writes_threshold=3", "", StoreSpec{Path: "/mnt/hda1",
+			L0CompactionThreshold: 8, BytesPerSync: 1 << 20,
+			PebbleOptions: "[Options]\nmem_table_stop_writes_threshold=6\n[Options]\nmem_table_stop_writes_threshold=3"}},
+		{"path=/mnt/hda1,l0-compaction-threshold=8,preset=read_optimized", "", StoreSpec{Path: "/mnt/hda1",
+			L0CompactionThreshold: 8, PebbleOptions: "[Level \"6\"]\ncompression=ZSTD"}},
+		{"path=/mnt/hda1,preset=balanced", "", StoreSpec{Path: "/mnt/hda1"}},
+		{"path=/mnt/hda1,preset=fast", "fast is not a valid store preset", StoreSpec{}},
+
 		// RocksDB
 		{"path=/,rocksdb=key1=val1;key2=val2", "", StoreSpec{Path: "/", RocksDBOptions: "key1=val1;key2=val2"}},
 
@@ -223,12 +285,90 @@ target_file_size=2097152`
 	}
 }
 
+// TestStoreSpecPathRoundTrip verifies that store paths containing the
+// characters escaped in store specs survive String() and NewStoreSpec.
+func TestStoreSpecPathRoundTrip(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, path := range []string{
+		`/mnt/a,b`,
+		`/mnt/a:b`,
+		`/mnt/a\b`,
+		`/mnt/a\,b`,
+		`/mnt/a\:b`,
+		`/mnt/a\\,:b`,
+		`/mnt/dir\`,
+		`/mnt/$HOME`,
+		`/mnt/a$1`,
+		`/mnt/${HOME}`,
+		`/mnt/$${x}`,
+	} {
+		spec := StoreSpec{Path: path, Attributes: roachpb.Attributes{Attrs: []string{"ssd"}}}
+		parsed, err := base.NewStoreSpec(spec.String())
+		require.NoError(t, err, spec.String())
+		require.Equal(t, path, parsed.Path, spec.String())
+		require.Equal(t, spec.String(), parsed.String())
+	}
+}
+
 // StoreSpec aliases base.StoreSpec for convenience.
 type StoreSpec = base.StoreSpec
 
 // SizeSpec aliases base.SizeSpec for convenience.
 type SizeSpec = base.SizeSpec
 
+// TestNewStoreSpecPathExpansion verifies that ${VAR} references to environment
+// variables are expanded in store paths, and only there.
+func TestNewStoreSpecPathExpansion(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer envutil.TestSetEnv(t, "STORE_SPEC_TEST_DATA_DIR", "/mnt/data")()
+	defer envutil.TestUnsetEnv(t, "STORE_SPEC_TEST_UNSET")()
+
+	for _, tc := range []struct {
+		value string
+		path  string
+	}{
+		{"path=${STORE_SPEC_TEST_DATA_DIR}/n1", "/mnt/data/n1"},
+		{"${STORE_SPEC_TEST_DATA_DIR}/n1", "/mnt/data/n1"},
+		// Only the braced form is expanded, so paths which contain a $ keep their
+		// meaning.
+		{"path=/mnt/a$1", "/mnt/a$1"},
+		{"path=/mnt/$STORE_SPEC_TEST_DATA_DIR", "/mnt/$STORE_SPEC_TEST_DATA_DIR"},
+		{"path=/mnt/a$$b$", "/mnt/a$$b$"},
+		// $${ stands for a literal ${.
+		{"path=/mnt/$${STORE_SPEC_TEST_DATA_DIR}", "/mnt/${STORE_SPEC_TEST_DATA_DIR}"},
+	} {
+		spec, err := base.NewStoreSpec(tc.value)
+		require.NoError(t, err, tc.value)
+		require.Equal(t, tc.path, spec.Path, tc.value)
+		parsed, err := base.NewStoreSpec(spec.String())
+		require.NoError(t, err, spec.String())
+		require.Equal(t, spec.Path, parsed.Path)
+	}
+
+	for _, tc := range []struct {
+		value  string
+		expErr string
+	}{
+		{"path=/mnt/${STORE_SPEC_TEST_UNSET}",
+			"store path /mnt/${STORE_SPEC_TEST_UNSET} references the unset environment variable STORE_SPEC_TEST_UNSET"},
+		{"path=/mnt/${STORE_SPEC_TEST_DATA_DIR",
+			"store path /mnt/${STORE_SPEC_TEST_DATA_DIR has an unterminated ${ reference"},
+		{"path=/mnt/${}",
+			`store path /mnt/${} references an invalid environment variable name ""`},
+		{"path=/mnt/${1a}",
+			`store path /mnt/${1a} references an invalid environment variable name "1a"`},
+	} {
+		_, err := base.NewStoreSpec(tc.value)
+		require.EqualError(t, err, tc.expErr, tc.value)
+	}
+
+	// Attributes are not expanded.
+	spec, err := base.NewStoreSpec("path=/mnt/a,attrs=${STORE_SPEC_TEST_DATA_DIR}")
+	require.NoError(t, err)
+	require.Equal(t, []string{"${STORE_SPEC_TEST_DATA_DIR}"}, spec.Attributes.Attrs)
+}
+
 func TestJoinListType(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
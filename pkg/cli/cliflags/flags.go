@@ -105,7 +105,8 @@ var (
 		Description: `
 An ordered, colon-separated list of node attributes. Attributes are arbitrary
 strings specifying machine capabilities. Machine capabilities might include
-specialized hardware or number of cores (e.g. "gpu", "x16c"). For example:
+specialized hardware or number of cores (e.g. "gpu", "x16c"). Attributes may
+not contain ",", and repeated attributes are ignored. For example:
 <PRE>
 
   --attrs=x16c:gpu</PRE>`,
@@ -678,6 +679,19 @@ apply. This flag is experimental.
 `,
 	}
 
+	AcceptInsecureExposure = FlagInfo{
+		Name:   "accept-insecure-exposure",
+		EnvVar: "COCKROACH_ACCEPT_INSECURE_EXPOSURE",
+		Description: `
+When specified together with --insecure, this node may listen on and advertise
+hosts other than loopback hosts. Without it, an insecure node refuses to start
+if any of its listen and advertise addresses (RPC, SQL or HTTP) names a
+non-loopback host, since any client able to reach an insecure node has full
+access to the cluster. Addresses which leave the host unspecified, like the
+defaults, are always accepted.
+`,
+	}
+
 	LocalityAdvertiseAddr = FlagInfo{
 		Name: "locality-advertise-addr",
 		Description: `
@@ -458,6 +458,7 @@ func init() {
 		// The following flag is planned to become non-experimental in 21.1.
 		cliflagcfg.BoolFlag(f, &serverCfg.AcceptSQLWithoutTLS, cliflags.AcceptSQLWithoutTLS)
 		_ = f.MarkHidden(cliflags.AcceptSQLWithoutTLS.Name)
+		cliflagcfg.BoolFlag(f, &serverCfg.AcceptInsecureExposure, cliflags.AcceptInsecureExposure)
 
 		// More server flags.
 
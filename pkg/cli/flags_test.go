@@ -143,7 +143,6 @@ func TestMemoryPoolFlagValues(t *testing.T) {
 			}{
 				{"100MB", 100 * 1000 * 1000},
 				{".5GiB", 512 * 1024 * 1024},
-				{"1.3", 1},
 			}
 			for _, c := range testCases {
 				args := []string{tc.flag, c.value}
@@ -155,6 +154,12 @@ func TestMemoryPoolFlagValues(t *testing.T) {
 				}
 			}
 
+			// Decimals of 1 or more without a unit are rejected rather than
+			// truncated to a byte count.
+			if err := f.Parse([]string{tc.flag, "1.3"}); !testutils.IsError(err, "a decimal without a unit must be a fraction below 1") {
+				t.Errorf("expected an error for 1.3, got %v", err)
+			}
+
 			for _, c := range []string{".30", "0.3"} {
 				args := []string{tc.flag, c}
 				if err := f.Parse(args); err != nil {
@@ -14,7 +14,6 @@ import (
 	gohex "encoding/hex"
 	"fmt"
 	"math"
-	"regexp"
 	"strconv"
 	"strings"
 
@@ -375,24 +374,15 @@ func makeBytesOrPercentageValue(
 	}
 }
 
-var fractionRE = regexp.MustCompile(`^0?\.[0-9]+$`)
-
 // Set implements the pflags.Flag interface.
 func (b *bytesOrPercentageValue) Set(s string) error {
 	b.origVal = s
-	if strings.HasSuffix(s, "%") || fractionRE.MatchString(s) {
-		multiplier := 100.0
-		if s[len(s)-1] == '%' {
-			// We have a percentage.
-			multiplier = 1.0
-			s = s[:len(s)-1]
-		}
-		// The user can express .123 or 0.123. Parse as float.
-		frac, err := strconv.ParseFloat(s, 32)
-		if err != nil {
-			return err
-		}
-		percent := int(frac * multiplier)
+	size, err := humanizeutil.ParseByteSize(s)
+	if err != nil {
+		return err
+	}
+	if size.IsPercent {
+		percent := int(size.Percent)
 		if percent < 1 || percent > 99 {
 			return fmt.Errorf("percentage %d%% out of range 1%% - 99%%", percent)
 		}
@@ -13,6 +13,8 @@ set ::env(COCKROACH_CONNECT_TIMEOUT) 15
 set ::env(COCKROACH_SQL_CLI_HISTORY) $histfile
 # Set client commands as insecure. The server uses --insecure.
 set ::env(COCKROACH_INSECURE) "true"
+# Some servers listen on non-loopback hosts.
+set ::env(COCKROACH_ACCEPT_INSECURE_EXPOSURE) "true"
 system "rm -f $histfile"
 
 # Everything in this test should be fast. Don't be tolerant for long
@@ -148,6 +148,11 @@ func setupLogging(ctx context.Context, cmd *cobra.Command, isServerCmd, applyCon
 	if isServerCmd && len(h.Config.Sinks.FileGroups) == 0 {
 		addPredefinedLogFiles(&h.Config)
 	}
+	if isServerCmd {
+		if err := serverCfg.ConfigureAuditLog(&h.Config); err != nil {
+			return err
+		}
+	}
 
 	// Our configuration is complete. Validate it.
 	// This ensures that all optional fields are populated and
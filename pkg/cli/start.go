@@ -622,6 +622,19 @@ func runStartInternal(
 	// registered.
 	reportConfiguration(ctx)
 
+	// Surface expired and expiring certificates now rather than at the first
+	// connection which uses them.
+	const certExpiryWarnWithin = 7 * 24 * time.Hour
+	if err := serverCfg.CheckCertExpiry(ctx, certExpiryWarnWithin); err != nil {
+		return err
+	}
+
+	// Each warning was logged as it was found; repeat them in a single block
+	// so that they don't scroll past unnoticed.
+	if warnings := serverCfg.Warnings(); len(warnings) > 0 {
+		log.Ops.Shoutf(ctx, severity.WARNING, "config warnings:\n- %s", strings.Join(warnings, "\n- "))
+	}
+
 	// ReadyFn will be called when the server has started listening on
 	// its network sockets, but perhaps before it has done bootstrapping
 	// and thus before Start() completes.
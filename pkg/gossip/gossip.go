@@ -255,6 +255,11 @@ type Gossip struct {
 	bootstrapAddrs map[util.UnresolvedAddr]roachpb.NodeID
 
 	locality roachpb.Locality
+
+	// expectedClusterSize is a lower bound on the size of the gossip network
+	// used to size the peer sets, until the node has learned about at least
+	// as many nodes. Protected by mu.
+	expectedClusterSize int
 }
 
 // New creates an instance of a gossip node.
@@ -798,11 +803,26 @@ func (g *Gossip) recomputeMaxPeersLocked() {
 		n++
 		return true
 	})
+	if n < g.expectedClusterSize {
+		n = g.expectedClusterSize
+	}
 	maxPeers := maxPeers(n)
 	g.mu.incoming.setMaxSize(maxPeers)
 	g.outgoing.setMaxSize(maxPeers)
 }
 
+// SetExpectedClusterSize sets the number of nodes the cluster is expected to
+// have. Until the node learns about that many nodes through gossip, it sizes
+// its incoming and outgoing peer sets for the expected size, so that a node
+// joining a large cluster connects to enough peers from the start. Zero
+// restores sizing by the number of known nodes only.
+func (g *Gossip) SetExpectedClusterSize(n int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.expectedClusterSize = n
+	g.recomputeMaxPeersLocked()
+}
+
 // getNodeDescriptor looks up the descriptor of the node by ID. The method
 // accepts a flag indicating whether the mutex is held by the caller. This
 // method is called externally via GetNodeDescriptor and internally by
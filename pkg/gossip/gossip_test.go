@@ -331,6 +331,36 @@ func TestGossipOutgoingLimitEnforced(t *testing.T) {
 	local.clientsMu.Unlock()
 }
 
+// TestGossipExpectedClusterSize verifies that the expected cluster size
+// raises the peer limits until the node knows about that many nodes.
+func TestGossipExpectedClusterSize(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	g := NewTest(1, stopper, metric.NewRegistry())
+
+	maxSizes := func() (incoming, outgoing int) {
+		g.mu.RLock()
+		defer g.mu.RUnlock()
+		return g.mu.incoming.maxSize, g.outgoing.maxSize
+	}
+	incoming, outgoing := maxSizes()
+	require.Equal(t, minPeers, incoming)
+	require.Equal(t, minPeers, outgoing)
+
+	g.SetExpectedClusterSize(1000)
+	require.Greater(t, maxPeers(1000), minPeers)
+	incoming, outgoing = maxSizes()
+	require.Equal(t, maxPeers(1000), incoming)
+	require.Equal(t, maxPeers(1000), outgoing)
+
+	g.SetExpectedClusterSize(0)
+	incoming, outgoing = maxSizes()
+	require.Equal(t, minPeers, incoming)
+	require.Equal(t, minPeers, outgoing)
+}
+
 func TestGossipMostDistant(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
@@ -600,6 +600,11 @@ type Allocator struct {
 	randGen allocatorRand
 	Metrics AllocatorMetrics
 
+	// PreferLocalLeases, if set, makes ShouldTransferLease keep leases whose
+	// leaseholder satisfies the range's lease preferences, rather than
+	// transferring them away to balance lease counts or follow the workload.
+	PreferLocalLeases bool
+
 	knobs *allocator.TestingKnobs
 }
 
@@ -2661,6 +2666,13 @@ func (a *Allocator) ShouldTransferLease(
 		return TransferLeaseForIOOverload
 	}
 
+	if a.PreferLocalLeases && a.leaseholderSatisfiesPreferences(storePool, conf, leaseRepl.StoreID(), existing) {
+		log.KvDistribution.VEventf(ctx, 3,
+			"ShouldTransferLease (lease-holder=s%d): keeping lease that satisfies lease preferences",
+			leaseRepl.StoreID())
+		return DontTransferLeaseBalanced
+	}
+
 	existing = a.ValidLeaseTargets(
 		ctx,
 		storePool,
@@ -3001,6 +3013,23 @@ func (a Allocator) PreferredLeaseholders(
 	return nil
 }
 
+// leaseholderSatisfiesPreferences returns whether the range has lease
+// preferences and the leaseholder is among the existing replicas that satisfy
+// them.
+func (a Allocator) leaseholderSatisfiesPreferences(
+	storePool storepool.AllocatorStorePool,
+	conf *roachpb.SpanConfig,
+	leaseStoreID roachpb.StoreID,
+	existing []roachpb.ReplicaDescriptor,
+) bool {
+	for _, repl := range a.PreferredLeaseholders(storePool, conf, existing) {
+		if repl.StoreID == leaseStoreID {
+			return true
+		}
+	}
+	return false
+}
+
 // computeQuorum computes the quorum value for the given number of nodes.
 func computeQuorum(nodes int) int {
 	return (nodes / 2) + 1
@@ -3154,6 +3154,88 @@ func TestAllocatorLeasePreferences(t *testing.T) {
 	}
 }
 
+// TestAllocatorPreferLocalLeases verifies that an allocator configured with
+// PreferLocalLeases keeps leases held by a store satisfying the lease
+// preferences, while still moving leases away for the preferences themselves.
+func TestAllocatorPreferLocalLeases(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+	stopper, g, sp, a, _ := CreateTestAllocator(ctx, 10, true /* deterministic */)
+	defer stopper.Stop(ctx)
+
+	// 4 stores with distinct localities where the lease count for each store
+	// is equal to 100x the store ID.
+	var stores []*roachpb.StoreDescriptor
+	for i := 1; i <= 4; i++ {
+		stores = append(stores, &roachpb.StoreDescriptor{
+			StoreID: roachpb.StoreID(i),
+			Node: roachpb.NodeDescriptor{
+				NodeID: roachpb.NodeID(i),
+				Locality: roachpb.Locality{
+					Tiers: []roachpb.Tier{
+						{Key: "dc", Value: strconv.Itoa(i)},
+					},
+				},
+			},
+			Capacity: roachpb.StoreCapacity{LeaseCount: int32(100 * i)},
+		})
+	}
+	sg := gossiputil.NewStoreGossiper(g)
+	sg.GossipStores(stores, t)
+
+	preferNotDC1 := []roachpb.LeasePreference{
+		{Constraints: []roachpb.Constraint{{Key: "dc", Value: "1", Type: roachpb.Constraint_PROHIBITED}}},
+	}
+	preferMatchesNothing := []roachpb.LeasePreference{
+		{Constraints: []roachpb.Constraint{{Key: "dc", Value: "5", Type: roachpb.Constraint_REQUIRED}}},
+	}
+
+	testCases := []struct {
+		leaseholder       roachpb.StoreID
+		existing          []roachpb.ReplicaDescriptor
+		preferences       []roachpb.LeasePreference
+		expect            TransferLeaseDecision
+		expectPreferLocal TransferLeaseDecision
+	}{
+		// The leaseholder satisfies the preferences, but has more leases than
+		// the other preferred stores.
+		{4, replicas(1, 2, 3, 4), preferNotDC1, TransferLeaseForCountBalance, DontTransferLeaseBalanced},
+		// The leaseholder violates the preferences.
+		{1, replicas(1, 2, 3, 4), preferNotDC1, TransferLeaseForPreferences, TransferLeaseForPreferences},
+		// The range has no satisfiable preferences.
+		{4, replicas(1, 3, 4), preferMatchesNothing, TransferLeaseForCountBalance, TransferLeaseForCountBalance},
+		// The range has no preferences.
+		{4, replicas(1, 3, 4), nil, TransferLeaseForCountBalance, TransferLeaseForCountBalance},
+	}
+
+	for _, c := range testCases {
+		t.Run("", func(t *testing.T) {
+			conf := &roachpb.SpanConfig{LeasePreferences: c.preferences}
+			for _, preferLocal := range []bool{false, true} {
+				a.PreferLocalLeases = preferLocal
+				result := a.ShouldTransferLease(
+					ctx,
+					sp,
+					&roachpb.RangeDescriptor{},
+					conf,
+					c.existing,
+					&mockRepl{
+						storeID:           c.leaseholder,
+						replicationFactor: int32(len(c.existing)),
+					},
+					allocator.RangeUsageInfo{},
+				)
+				expect := c.expect
+				if preferLocal {
+					expect = c.expectPreferLocal
+				}
+				require.Equal(t, expect, result, "preferLocalLeases=%t", preferLocal)
+			}
+		})
+	}
+}
+
 func TestAllocatorLeasePreferencesMultipleStoresPerLocality(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -79,6 +79,10 @@ type StoreDetail struct {
 	// LastUnavailable is set when it's detected that a store was unavailable,
 	// i.e. failed liveness.
 	LastUnavailable hlc.Timestamp
+	// JoinedAt is set when the first descriptor gossiped by a store reports no
+	// replicas, i.e. when the store is joining the cluster. It is empty for
+	// stores which already held replicas when they were first seen.
+	JoinedAt hlc.Timestamp
 }
 
 // storeStatus is the current status of a store.
@@ -344,6 +348,10 @@ type StorePool struct {
 	DetailsMu struct {
 		syncutil.RWMutex
 		StoreDetails map[roachpb.StoreID]*StoreDetail
+		// joinRebalanceRate is the rate, in replicas per second, at which
+		// replicas may be moved onto a store which joined the cluster. Zero
+		// disables the limit.
+		joinRebalanceRate float64
 	}
 	localitiesMu struct {
 		syncutil.RWMutex
@@ -471,6 +479,8 @@ func (sp *StorePool) storeDescriptorUpdate(storeDesc roachpb.StoreDescriptor) {
 	detail := sp.GetStoreDetailLocked(storeID)
 	if detail.Desc != nil {
 		oldCapacity = detail.Desc.Capacity
+	} else if curCapacity.RangeCount == 0 {
+		detail.JoinedAt = now
 	}
 	detail.Desc = &storeDesc
 	detail.LastUpdatedTime = now
@@ -897,6 +907,29 @@ func (sp *StorePool) liveAndDeadReplicasWithLiveness(
 	return
 }
 
+// SetJoinRebalanceRate limits the rate, in replicas per second, at which
+// replicas are moved onto stores which join the cluster. A joining store is
+// considered throttled while it holds at least as many replicas as the rate
+// allows for the time since it joined, so its allowance ramps up from zero.
+// A rate of zero disables the limit.
+func (sp *StorePool) SetJoinRebalanceRate(rate float64) {
+	sp.DetailsMu.Lock()
+	defer sp.DetailsMu.Unlock()
+	sp.DetailsMu.joinRebalanceRate = rate
+}
+
+// joinRebalanceThrottledLocked returns whether the store joined the cluster
+// and already received the replicas allowed by the join rebalance rate. It
+// requires that DetailsMu is held.
+func (sp *StorePool) joinRebalanceThrottledLocked(detail *StoreDetail, now hlc.Timestamp) bool {
+	rate := sp.DetailsMu.joinRebalanceRate
+	if rate <= 0 || detail.JoinedAt.IsEmpty() || detail.Desc == nil {
+		return false
+	}
+	elapsed := now.GoTime().Sub(detail.JoinedAt.GoTime())
+	return float64(detail.Desc.Capacity.RangeCount) >= rate*elapsed.Seconds()
+}
+
 // SetOnCapacityChange installs a callback to be called when any store
 // capacity changes in the storepool. This currently doesn't consider local
 // updates (UpdateLocalStoreAfterRelocate, UpdateLocalStoreAfterRebalance,
@@ -1181,6 +1214,12 @@ func (sp *StorePool) getStoreListFromIDsLocked(
 			}
 		case storeStatusAvailable:
 			aliveStoreCount++
+			if sp.joinRebalanceThrottledLocked(detail, now) {
+				throttled = append(throttled, fmt.Sprintf("s%d: join rebalance rate exceeded", storeID))
+				if filter == StoreFilterThrottled {
+					continue
+				}
+			}
 			storeDescriptors = append(storeDescriptors, *detail.Desc)
 		case storeStatusDraining:
 			throttled = append(throttled, fmt.Sprintf("s%d: draining", storeID))
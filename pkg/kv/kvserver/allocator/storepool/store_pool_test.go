@@ -19,6 +19,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/allocator"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/liveness/livenesspb"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
@@ -602,6 +603,58 @@ func TestStorePoolThrottle(t *testing.T) {
 	}
 }
 
+func TestStorePoolJoinRebalanceRate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	stopper, g, mc, sp, _ := CreateTestStorePool(ctx, st,
+		liveness.TestTimeUntilNodeDead, true, /* deterministic */
+		func() int { return 10 }, /* nodeCount */
+		livenesspb.NodeLivenessStatus_LIVE)
+	defer stopper.Stop(ctx)
+
+	// Store 1 joins the cluster, store 2 already holds replicas.
+	sg := gossiputil.NewStoreGossiper(g)
+	sg.GossipStores([]*roachpb.StoreDescriptor{
+		{StoreID: 1, Node: roachpb.NodeDescriptor{NodeID: 1}},
+		{StoreID: 2, Node: roachpb.NodeDescriptor{NodeID: 2}, Capacity: roachpb.StoreCapacity{RangeCount: 10}},
+	}, t)
+	sp.SetJoinRebalanceRate(2 /* replicas per second */)
+
+	candidates := func() (ids []roachpb.StoreID) {
+		sl, _, _ := sp.GetStoreList(StoreFilterThrottled)
+		for _, desc := range sl.Stores {
+			ids = append(ids, desc.StoreID)
+		}
+		return ids
+	}
+	addReplicas := func(n int) {
+		for i := 0; i < n; i++ {
+			sp.UpdateLocalStoreAfterRebalance(1, allocator.RangeUsageInfo{}, roachpb.ADD_VOTER)
+		}
+	}
+
+	// The joining store has no allowance yet.
+	require.Equal(t, []roachpb.StoreID{2}, candidates())
+	// After 3s, it may receive 6 replicas.
+	mc.Advance(3 * time.Second)
+	require.Equal(t, []roachpb.StoreID{1, 2}, candidates())
+	addReplicas(5)
+	require.Equal(t, []roachpb.StoreID{1, 2}, candidates())
+	addReplicas(1)
+	require.Equal(t, []roachpb.StoreID{2}, candidates())
+	// The allowance keeps growing.
+	mc.Advance(time.Second)
+	require.Equal(t, []roachpb.StoreID{1, 2}, candidates())
+
+	addReplicas(10)
+	require.Equal(t, []roachpb.StoreID{2}, candidates())
+	// Without a rate, the joining store is not throttled.
+	sp.SetJoinRebalanceRate(0)
+	require.Equal(t, []roachpb.StoreID{1, 2}, candidates())
+}
+
 // See state transition diagram in storeDetail.status() for a visual
 // representation of what this test asserts.
 func TestStorePoolSuspected(t *testing.T) {
@@ -671,6 +671,14 @@ var (
 		Measurement: "Nanoseconds",
 		Unit:        metric.Unit_NANOSECONDS,
 	}
+	metaRdbWriteThroughput = metric.Metadata{
+		Name: "storage.write-throughput",
+		Help: "Bytes per second written by the storage engine to the WAL and by flushes and " +
+			"compactions, since the previous sample. Only populated for stores which enable " +
+			"the sample-throughput store option",
+		Measurement: "Bytes/Sec",
+		Unit:        metric.Unit_BYTES,
+	}
 
 	metaRdbCheckpoints = metric.Metadata{
 		Name: "storage.checkpoints",
@@ -2606,6 +2614,7 @@ type StoreMetrics struct {
 	RdbLevelScore                     [7]*metric.GaugeFloat64 // idx = level
 	RdbWriteStalls                    *metric.Gauge
 	RdbWriteStallNanos                *metric.Gauge
+	RdbWriteThroughput                *metric.Gauge
 	SingleDelInvariantViolations      *metric.Gauge
 	SingleDelIneffectualCount         *metric.Gauge
 	SharedStorageBytesRead            *metric.Gauge
@@ -3316,6 +3325,7 @@ func newStoreMetrics(histogramWindow time.Duration) *StoreMetrics {
 		RdbLevelScore:                     rdbLevelScore,
 		RdbWriteStalls:                    metric.NewGauge(metaRdbWriteStalls),
 		RdbWriteStallNanos:                metric.NewGauge(metaRdbWriteStallNanos),
+		RdbWriteThroughput:                metric.NewGauge(metaRdbWriteThroughput),
 		IterBlockBytes:                    metric.NewGauge(metaBlockBytes),
 		IterBlockBytesInCache:             metric.NewGauge(metaBlockBytesInCache),
 		IterBlockReadDuration:             metric.NewGauge(metaBlockReadDuration),
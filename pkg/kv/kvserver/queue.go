@@ -296,6 +296,10 @@ type queueImpl interface {
 // replicaInQueue.
 type queueProcessTimeoutFunc func(*cluster.Settings, replicaInQueue) time.Duration
 
+// ConfigurableQueueNames lists the queues whose concurrency can be overridden
+// through StoreConfig.QueueConcurrency.
+var ConfigurableQueueNames = []string{"replicate", "mvccGC", "split", "merge"}
+
 type queueConfig struct {
 	// maxSize is the maximum number of replicas to queue.
 	maxSize int
@@ -464,6 +468,9 @@ func newBaseQueue(name string, impl queueImpl, store *Store, cfg queueConfig) *b
 	if cfg.processTimeoutFunc == nil {
 		cfg.processTimeoutFunc = defaultProcessTimeoutFunc
 	}
+	if n, ok := store.cfg.QueueConcurrency[name]; ok && n > 0 {
+		cfg.maxConcurrency = n
+	}
 	if cfg.maxConcurrency == 0 {
 		cfg.maxConcurrency = 1
 	}
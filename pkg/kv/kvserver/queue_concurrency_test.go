@@ -31,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -193,3 +194,32 @@ func (fr *fakeReplica) redirectOnOrAcquireLease(
 func (fr *fakeReplica) CurrentLeaseStatus(context.Context) kvserverpb.LeaseStatus {
 	return kvserverpb.LeaseStatus{}
 }
+
+// TestBaseQueueConcurrencyOverride verifies that StoreConfig.QueueConcurrency
+// overrides the concurrency of the queues it names, and only those.
+func TestBaseQueueConcurrencyOverride(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	store := &Store{
+		cfg: StoreConfig{
+			AmbientCtx:       log.MakeTestingAmbientContext(tracing.NewTracer()),
+			Settings:         cluster.MakeTestingClusterSettings(),
+			QueueConcurrency: map[string]int{"split": 7},
+		},
+	}
+	cfg := queueConfig{
+		maxConcurrency:       4,
+		acceptsUnsplitRanges: true,
+		disabledConfig:       testQueueEnabled,
+	}
+	impl := fakeQueueImpl{}
+
+	split := newBaseQueue("split", impl, store, cfg)
+	require.Equal(t, 7, split.maxConcurrency)
+	require.Equal(t, 7, cap(split.processSem))
+
+	merge := newBaseQueue("merge", impl, store, cfg)
+	require.Equal(t, 4, merge.maxConcurrency)
+	require.Equal(t, 4, cap(merge.processSem))
+}
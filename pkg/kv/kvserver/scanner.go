@@ -77,6 +77,15 @@ type replicaScanner struct {
 	replicas       replicaSet     // Replicas to be scanned
 	queues         []replicaQueue // Replica queues managed by this scanner
 	removed        chan *Replica  // Replicas to remove from queues
+	// needsPriority, if set, identifies replicas which are revisited between
+	// the replicas of the regular scan (see PrioritizeReplicas). The priority
+	// lane is only accessed by the scan loop.
+	needsPriority func(context.Context, *Replica) bool
+	priority      struct {
+		replicas []*Replica
+		next     int
+		ids      map[roachpb.RangeID]struct{}
+	}
 	// Count of times and total duration through the scanning loop.
 	mu struct {
 		syncutil.Mutex
@@ -125,6 +134,19 @@ func (rs *replicaScanner) AddQueues(queues ...replicaQueue) {
 	rs.queues = append(rs.queues, queues...)
 }
 
+// PrioritizeReplicas configures the scanner to maintain a priority lane of the
+// replicas for which needsPriority returns true, such as those of
+// under-replicated ranges. Replicas enter the lane when the regular scan
+// visits them, and one replica of the lane is offered to the queues after each
+// replica of the regular scan until needsPriority no longer holds for it. This
+// means that prioritized replicas are visited many times per scan interval,
+// without slowing down the regular scan. This method may only be called before
+// Start().
+func (rs *replicaScanner) PrioritizeReplicas(needsPriority func(context.Context, *Replica) bool) {
+	rs.needsPriority = needsPriority
+	rs.priority.ids = make(map[roachpb.RangeID]struct{})
+}
+
 // Start spins up the scanning loop.
 func (rs *replicaScanner) Start() {
 	for _, queue := range rs.queues {
@@ -234,8 +256,9 @@ func (rs *replicaScanner) waitAndProcess(ctx context.Context, start time.Time, r
 			if log.V(2) {
 				log.Infof(ctx, "replica scanner processing %s", repl)
 			}
-			for _, q := range rs.queues {
-				q.MaybeAddAsync(ctx, repl, rs.clock.NowAsClockTimestamp())
+			rs.addToQueues(ctx, repl)
+			if rs.needsPriority != nil {
+				rs.processPriority(ctx, repl)
 			}
 			return false
 
@@ -248,6 +271,59 @@ func (rs *replicaScanner) waitAndProcess(ctx context.Context, start time.Time, r
 	}
 }
 
+func (rs *replicaScanner) addToQueues(ctx context.Context, repl *Replica) {
+	for _, q := range rs.queues {
+		q.MaybeAddAsync(ctx, repl, rs.clock.NowAsClockTimestamp())
+	}
+}
+
+// processPriority adds repl, which was just visited by the regular scan, to the
+// priority lane if it needs priority, and then offers the next replica of the
+// lane to the queues. Replicas which no longer need priority are dropped from
+// the lane.
+func (rs *replicaScanner) processPriority(ctx context.Context, repl *Replica) {
+	if _, ok := rs.priority.ids[repl.RangeID]; !ok && rs.needsPriority(ctx, repl) {
+		rs.priority.ids[repl.RangeID] = struct{}{}
+		rs.priority.replicas = append(rs.priority.replicas, repl)
+		// The replica was just added to the queues.
+		return
+	}
+	for len(rs.priority.replicas) > 0 {
+		if rs.priority.next >= len(rs.priority.replicas) {
+			rs.priority.next = 0
+		}
+		next := rs.priority.replicas[rs.priority.next]
+		if !rs.needsPriority(ctx, next) {
+			rs.removeFromPriority(next.RangeID)
+			continue
+		}
+		rs.priority.next++
+		if log.V(2) {
+			log.Infof(ctx, "replica scanner processing prioritized %s", next)
+		}
+		rs.addToQueues(ctx, next)
+		return
+	}
+}
+
+// removeFromPriority removes the replica with the given range ID from the
+// priority lane, if present.
+func (rs *replicaScanner) removeFromPriority(rangeID roachpb.RangeID) {
+	if _, ok := rs.priority.ids[rangeID]; !ok {
+		return
+	}
+	delete(rs.priority.ids, rangeID)
+	for i, repl := range rs.priority.replicas {
+		if repl.RangeID == rangeID {
+			rs.priority.replicas = append(rs.priority.replicas[:i], rs.priority.replicas[i+1:]...)
+			if i < rs.priority.next {
+				rs.priority.next--
+			}
+			return
+		}
+	}
+}
+
 func (rs *replicaScanner) removeReplica(repl *Replica) {
 	// Remove replica from all queues as applicable. Note that we still
 	// process removals while disabled.
@@ -255,6 +331,7 @@ func (rs *replicaScanner) removeReplica(repl *Replica) {
 	for _, q := range rs.queues {
 		q.MaybeRemove(rangeID)
 	}
+	rs.removeFromPriority(rangeID)
 	if log.V(6) {
 		ctx := rs.AnnotateCtx(context.TODO())
 		log.Infof(ctx, "removed replica %s", repl)
@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 	"github.com/google/btree"
+	"github.com/stretchr/testify/require"
 )
 
 func makeAmbCtx() log.AmbientContext {
@@ -254,6 +255,88 @@ func TestScannerAddToQueues(t *testing.T) {
 	}
 }
 
+// visitCountingQueue is a testQueue which counts the times each range is
+// offered to it.
+type visitCountingQueue struct {
+	testQueue
+	visits map[roachpb.RangeID]int
+}
+
+func (vq *visitCountingQueue) MaybeAddAsync(
+	ctx context.Context, replI replicaInQueue, now hlc.ClockTimestamp,
+) {
+	vq.Lock()
+	defer vq.Unlock()
+	vq.visits[replI.(*Replica).RangeID]++
+}
+
+func (vq *visitCountingQueue) visitCounts() map[roachpb.RangeID]int {
+	vq.Lock()
+	defer vq.Unlock()
+	visits := make(map[roachpb.RangeID]int, len(vq.visits))
+	for rangeID, n := range vq.visits {
+		visits[rangeID] = n
+	}
+	return visits
+}
+
+// TestScannerPrioritizeReplicas verifies that replicas in the priority lane
+// are visited more often than the others, and only when prioritization is
+// enabled.
+func TestScannerPrioritizeReplicas(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	const count = 10
+	underReplicated := map[roachpb.RangeID]bool{3: true, 7: true}
+
+	testutils.RunTrueAndFalse(t, "prioritize", func(t *testing.T, prioritize bool) {
+		ranges := newTestRangeSet(count, t)
+		q := &visitCountingQueue{visits: make(map[roachpb.RangeID]int)}
+		q.SetDisabled(true)
+		clock := hlc.NewClockForTesting(timeutil.NewManualTime(timeutil.Unix(0, 123)))
+		s := newReplicaScanner(makeAmbCtx(), clock, 20*time.Millisecond, 0, 0, ranges)
+		s.AddQueues(q)
+		if prioritize {
+			s.PrioritizeReplicas(func(_ context.Context, repl *Replica) bool {
+				return underReplicated[repl.RangeID]
+			})
+		}
+		s.stopper = stop.NewStopper()
+		s.Start()
+		testutils.SucceedsSoon(t, func() error {
+			if n := s.scanCount(); n < 3 {
+				return errors.Errorf("only %d scans completed", n)
+			}
+			return nil
+		})
+		s.stopper.Stop(context.Background())
+
+		visits := q.visitCounts()
+		var minRegular, maxRegular int
+		for i := 0; i < count; i++ {
+			rangeID := roachpb.RangeID(i)
+			if underReplicated[rangeID] {
+				continue
+			}
+			if n := visits[rangeID]; minRegular == 0 || n < minRegular {
+				minRegular = n
+			}
+			if n := visits[rangeID]; n > maxRegular {
+				maxRegular = n
+			}
+		}
+		require.LessOrEqual(t, maxRegular-minRegular, 1)
+		for rangeID := range underReplicated {
+			if prioritize {
+				require.Greater(t, visits[rangeID], 2*maxRegular, "r%d", rangeID)
+			} else {
+				require.LessOrEqual(t, visits[rangeID], maxRegular, "r%d", rangeID)
+				require.GreaterOrEqual(t, visits[rangeID], minRegular, "r%d", rangeID)
+			}
+		}
+	})
+}
+
 // TestScannerTiming verifies that ranges are scanned, regardless
 // of how many, to match scanInterval.
 func TestScannerTiming(t *testing.T) {
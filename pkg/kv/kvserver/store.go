@@ -1079,6 +1079,14 @@ type Store struct {
 		maxL0Size         *slidingwindow.Swag
 	}
 
+	// underReplicated records the ranges counted in the
+	// ranges.underreplicated metric by the last updateReplicationGauges, when
+	// StoreConfig.PrioritizeUnderReplicated is set.
+	underReplicated struct {
+		syncutil.Mutex
+		ranges map[roachpb.RangeID]struct{}
+	}
+
 	// lastIOOverloadLeaseShed tracks the last time the store attempted to shed
 	// all range leases it held due to becoming IO overloaded.
 	lastIOOverloadLeaseShed atomic.Value
@@ -1112,6 +1120,17 @@ type Store struct {
 
 	// diskMonitor provides metrics for the disk associated with this store.
 	diskMonitor *disk.Monitor
+
+	// writeThroughput samples the write throughput of the engine, if enabled
+	// by EnableWriteThroughputSampling.
+	writeThroughput struct {
+		syncutil.Mutex
+		enabled bool
+		// lastBytes and lastTime are the cumulative bytes written by the
+		// engine, and the time, at the previous sample.
+		lastBytes uint64
+		lastTime  time.Time
+	}
 }
 
 var _ kv.Sender = &Store{}
@@ -1179,6 +1198,21 @@ type StoreConfig struct {
 	// stores.
 	ScanMaxIdleTime time.Duration
 
+	// PrioritizeUnderReplicated, if set, makes the scanner revisit the
+	// replicas of under-replicated ranges more often than once per
+	// ScanInterval.
+	PrioritizeUnderReplicated bool
+
+	// PreferLocalLeases, if set, makes the store hold on to the leases it
+	// acquires for ranges whose lease preferences it satisfies, instead of
+	// transferring them away for lease count or load balance.
+	PreferLocalLeases bool
+
+	// QueueConcurrency overrides the maximum number of replicas processed
+	// concurrently by the named queues, keyed by queue name (see
+	// ConfigurableQueueNames).
+	QueueConcurrency map[string]int
+
 	// If LogRangeAndNodeEvents is true, major changes to ranges will be logged into
 	// the range event log (system.rangelog table) and node join and restart
 	// events will be logged into the event log (system.eventlog table).
@@ -1487,6 +1521,7 @@ func NewStore(
 			}, cfg.TestingKnobs.AllocatorKnobs,
 		)
 	}
+	s.allocator.PreferLocalLeases = cfg.PreferLocalLeases
 	if s.metrics != nil {
 		s.metrics.registry.AddMetricStruct(s.allocator.Metrics.LoadBasedLeaseTransferMetrics)
 		s.metrics.registry.AddMetricStruct(s.allocator.Metrics.LoadBasedReplicaRebalanceMetrics)
@@ -1654,6 +1689,9 @@ func NewStore(
 			s.cfg.AmbientCtx, s.cfg.Clock, cfg.ScanInterval,
 			cfg.ScanMinIdleTime, cfg.ScanMaxIdleTime, newStoreReplicaVisitor(s),
 		)
+		if cfg.PrioritizeUnderReplicated {
+			s.scanner.PrioritizeReplicas(s.isUnderReplicated)
+		}
 		s.leaseQueue = newLeaseQueue(s, s.allocator)
 		s.mvccGCQueue = newMVCCGCQueue(s)
 		s.mergeQueue = newMergeQueue(s, s.db)
@@ -3213,6 +3251,10 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 	// We want to avoid having to read this multiple times during the replica
 	// visiting, so load it once up front for all nodes.
 	livenessMap := s.cfg.NodeLiveness.ScanNodeVitalityFromCache()
+	var underReplicatedRanges map[roachpb.RangeID]struct{}
+	if s.cfg.PrioritizeUnderReplicated {
+		underReplicatedRanges = make(map[roachpb.RangeID]struct{})
+	}
 	newStoreReplicaVisitor(s).Visit(func(rep *Replica) bool {
 		metrics := rep.Metrics(ctx, now, livenessMap, clusterNodes)
 		if metrics.Leader {
@@ -3255,6 +3297,9 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 			}
 			if metrics.Underreplicated {
 				underreplicatedRangeCount++
+				if underReplicatedRanges != nil {
+					underReplicatedRanges[rep.RangeID] = struct{}{}
+				}
 			}
 			if metrics.Overreplicated {
 				overreplicatedRangeCount++
@@ -3320,6 +3365,11 @@ func (s *Store) updateReplicationGauges(ctx context.Context) error {
 	s.metrics.RangeCount.Update(rangeCount)
 	s.metrics.UnavailableRangeCount.Update(unavailableRangeCount)
 	s.metrics.UnderReplicatedRangeCount.Update(underreplicatedRangeCount)
+	if underReplicatedRanges != nil {
+		s.underReplicated.Lock()
+		s.underReplicated.ranges = underReplicatedRanges
+		s.underReplicated.Unlock()
+	}
 	s.metrics.OverReplicatedRangeCount.Update(overreplicatedRangeCount)
 	s.metrics.RaftLogFollowerBehindCount.Update(behindCount)
 	s.metrics.RaftPausedFollowerCount.Update(pausedFollowerCount)
@@ -3446,6 +3496,19 @@ func (s *Store) checkpoint(tag string, spans []roachpb.Span) (string, error) {
 	return checkpointDir, nil
 }
 
+// isUnderReplicated returns whether the given replica is responsible for the
+// range-level metrics of its range, and the range is under-replicated, as
+// reported by the ranges.underreplicated metric. It reuses the replica metrics
+// computed by the last updateReplicationGauges rather than computing them for
+// every replica the scanner visits, so a range is only prioritized once the
+// metrics have caught up with it.
+func (s *Store) isUnderReplicated(_ context.Context, repl *Replica) bool {
+	s.underReplicated.Lock()
+	defer s.underReplicated.Unlock()
+	_, ok := s.underReplicated.ranges[repl.RangeID]
+	return ok
+}
+
 // computeMetrics is a common metric computation that is used by
 // ComputeMetricsPeriodically and ComputeMetrics to compute metrics.
 func (s *Store) computeMetrics(ctx context.Context) (m storage.Metrics, err error) {
@@ -3496,6 +3559,8 @@ func (s *Store) ComputeMetricsPeriodically(
 		s.metrics.updateDiskStats(rollingStats)
 	}
 
+	s.sampleWriteThroughput(m, timeutil.Now())
+
 	wt := m.Flush.WriteThroughput
 
 	updateWindowedHistogram := func(
@@ -3568,6 +3633,35 @@ func (s *Store) ComputeMetricsPeriodically(
 	return m, nil
 }
 
+// EnableWriteThroughputSampling makes ComputeMetricsPeriodically sample the
+// rate at which the engine writes to disk into the storage.write-throughput
+// metric.
+func (s *Store) EnableWriteThroughputSampling() {
+	s.writeThroughput.Lock()
+	defer s.writeThroughput.Unlock()
+	s.writeThroughput.enabled = true
+}
+
+// sampleWriteThroughput updates the write throughput metric, if sampling is
+// enabled, with the rate at which the engine wrote since the previous sample.
+// The first sample only records the baseline.
+func (s *Store) sampleWriteThroughput(m storage.Metrics, now time.Time) {
+	s.writeThroughput.Lock()
+	defer s.writeThroughput.Unlock()
+	if !s.writeThroughput.enabled {
+		return
+	}
+	_, compacted := m.CompactedBytes()
+	written := m.WAL.BytesWritten + m.Levels[0].BytesFlushed + compacted
+	if last := s.writeThroughput.lastTime; !last.IsZero() && now.After(last) &&
+		written >= s.writeThroughput.lastBytes {
+		rate := float64(written-s.writeThroughput.lastBytes) / now.Sub(last).Seconds()
+		s.metrics.RdbWriteThroughput.Update(int64(rate))
+	}
+	s.writeThroughput.lastBytes = written
+	s.writeThroughput.lastTime = now
+}
+
 // ComputeMetrics immediately computes the current value of store metrics which
 // cannot be computed incrementally. This method should be invoked periodically
 // by a higher-level system which records store metrics.
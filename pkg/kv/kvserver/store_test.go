@@ -782,6 +782,38 @@ func TestStoreRemoveReplicaDestroy(t *testing.T) {
 	}
 }
 
+// TestStoreWriteThroughputSampling verifies that the write throughput metric
+// is only recorded once sampling is enabled, and reflects the rate at which
+// the engine wrote since the previous sample.
+func TestStoreWriteThroughputSampling(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	store, _ := createTestStore(ctx, t, testStoreOpts{createSystemRanges: true}, stopper)
+
+	m := store.TODOEngine().GetMetrics()
+	walBytes := m.WAL.BytesWritten
+	now := timeutil.Now()
+	sample := func(written uint64, at time.Time) int64 {
+		m.WAL.BytesWritten = walBytes + written
+		store.sampleWriteThroughput(m, at)
+		return store.metrics.RdbWriteThroughput.Value()
+	}
+
+	// Sampling is disabled by default.
+	require.Zero(t, sample(0, now))
+	require.Zero(t, sample(10000, now.Add(10*time.Second)))
+
+	store.EnableWriteThroughputSampling()
+	// The first sample only records the baseline.
+	require.Zero(t, sample(10000, now.Add(10*time.Second)))
+	require.Equal(t, int64(1000), sample(20000, now.Add(20*time.Second)))
+	require.Equal(t, int64(500), sample(25000, now.Add(30*time.Second)))
+}
+
 func TestStoreReplicaVisitor(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -782,11 +782,11 @@ func (c *SyncedCluster) generateStartCmd(
 	return execStartTemplate(startTemplateData{
 		LogDir: c.LogDir(node, startOpts.VirtualClusterName, startOpts.SQLInstance),
 		KeyCmd: keyCmd,
-		EnvVars: append(append([]string{
+		EnvVars: append(append(append([]string{
 			fmt.Sprintf("ROACHPROD=%s", c.roachprodEnvValue(node)),
 			"GOTRACEBACK=crash",
 			"COCKROACH_SKIP_ENABLING_DIAGNOSTIC_REPORTING=1",
-		}, c.Env...), getEnvVars()...),
+		}, insecureExposureEnvVars(c.Secure)...), c.Env...), getEnvVars()...),
 		Binary:              cockroachNodeBinary(c, node),
 		Args:                args,
 		MemoryMax:           config.MemoryMax,
@@ -796,6 +796,16 @@ func (c *SyncedCluster) generateStartCmd(
 	})
 }
 
+// insecureExposureEnvVars returns the environment which lets the nodes of an
+// insecure cluster listen on non-loopback addresses. It is passed through the
+// environment rather than a flag, as older binaries do not know about it.
+func insecureExposureEnvVars(secure bool) []string {
+	if secure {
+		return nil
+	}
+	return []string{"COCKROACH_ACCEPT_INSECURE_EXPOSURE=true"}
+}
+
 type startTemplateData struct {
 	Local               bool
 	LogDir              string
@@ -113,6 +113,9 @@ func NewServerEx(
 		grpc.KeepaliveParams(serverKeepalive),
 		grpc.KeepaliveEnforcementPolicy(serverEnforcement),
 	}
+	if o.streamWorkers > 0 {
+		grpcOpts = append(grpcOpts, grpc.NumStreamWorkers(o.streamWorkers))
+	}
 	if !rpcCtx.ContextOptions.Insecure {
 		tlsConfig, err := rpcCtx.GetServerTLSConfig()
 		if err != nil {
@@ -302,6 +305,7 @@ type ContextOptions struct {
 	// or servers.
 	SSLCertsDir                    string
 	Insecure                       bool
+	MinTLSVersion                  string
 	ClusterName                    string
 	DisableClusterNameVerification bool
 	RPCHeartbeatInterval           time.Duration
@@ -401,6 +405,7 @@ func ServerContextOptionsFromBaseConfig(cfg *base.Config) ContextOptions {
 	return ContextOptions{
 		SSLCertsDir:                    cfg.SSLCertsDir,
 		Insecure:                       cfg.Insecure,
+		MinTLSVersion:                  cfg.MinTLSVersion,
 		ClusterName:                    cfg.ClusterName,
 		DisableClusterNameVerification: cfg.DisableClusterNameVerification,
 		RPCHeartbeatInterval:           cfg.RPCHeartbeatInterval,
@@ -520,6 +525,7 @@ func NewContext(ctx context.Context, opts ContextOptions) *Context {
 		SecurityContextOptions{
 			SSLCertsDir:       opts.SSLCertsDir,
 			Insecure:          opts.Insecure,
+			MinTLSVersion:     opts.MinTLSVersion,
 			AdvertiseAddrH:    opts.AdvertiseAddrH,
 			SQLAdvertiseAddrH: opts.SQLAdvertiseAddrH,
 			DisableTLSForHTTP: opts.DisableTLSForHTTP,
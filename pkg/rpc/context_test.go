@@ -2266,3 +2266,22 @@ func TestInitialHeartbeatFailedError(t *testing.T) {
 		return err
 	})
 }
+
+func TestWithStreamWorkers(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	var o serverOpts
+	require.Zero(t, o.streamWorkers)
+	WithStreamWorkers(0)(&o)
+	require.Zero(t, o.streamWorkers)
+	WithStreamWorkers(8)(&o)
+	require.Equal(t, uint32(8), o.streamWorkers)
+
+	// A server with stream workers is constructed like any other.
+	stopper := stop.NewStopper()
+	defer stopper.Stop(context.Background())
+	clock := timeutil.NewManualTime(timeutil.Unix(0, 1))
+	rpcCtx := newTestContext(uuid.MakeV4(), clock, time.Duration(0) /* maxOffset */, stopper)
+	_, err := NewServer(context.Background(), rpcCtx, WithStreamWorkers(8))
+	require.NoError(t, err)
+}
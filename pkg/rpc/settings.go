@@ -125,6 +125,9 @@ var sourceAddr = func() net.Addr {
 
 type serverOpts struct {
 	interceptor func(fullMethod string) error
+	// streamWorkers, if positive, is the number of goroutines serving
+	// incoming streams; see WithStreamWorkers.
+	streamWorkers uint32
 }
 
 // ServerOption is a configuration option passed to NewServer.
@@ -147,3 +150,11 @@ func WithInterceptor(f func(fullMethod string) error) ServerOption {
 		}
 	}
 }
+
+// WithStreamWorkers sets the number of goroutines the server uses to process
+// incoming streams. When zero, gRPC spawns a goroutine per stream.
+func WithStreamWorkers(n int) ServerOption {
+	return func(opts *serverOpts) {
+		opts.streamWorkers = uint32(n)
+	}
+}
@@ -59,6 +59,9 @@ func wrapError(err error) error {
 type SecurityContextOptions struct {
 	SSLCertsDir string
 	Insecure    bool
+	// MinTLSVersion is the minimum TLS version accepted by the TLS configs,
+	// as parsed by security.ParseTLSVersion.
+	MinTLSVersion string
 
 	// DisableTLSForHTTP is only used by GetUIServerTLSConfig().
 	//
@@ -122,6 +125,12 @@ func (ctx *SecurityContext) GetCertificateManager() (*security.CertificateManage
 		if !(ctx.useNodeAuth || ctx.tenID == roachpb.SystemTenantID) {
 			opts = append(opts, security.ForTenant(ctx.tenID.ToUint64()))
 		}
+		minTLSVersion, err := security.ParseTLSVersion(ctx.config.MinTLSVersion)
+		if err != nil {
+			ctx.lazy.certificateManager.err = err
+			return
+		}
+		opts = append(opts, security.WithMinTLSVersion(minTLSVersion))
 		ctx.lazy.certificateManager.cm, ctx.lazy.certificateManager.err =
 			security.NewCertificateManager(ctx.config.SSLCertsDir, ctx, opts...)
 
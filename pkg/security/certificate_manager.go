@@ -96,6 +96,9 @@ func makeCertificateManager(
 	for _, fn := range opts {
 		fn(&o)
 	}
+	if o.minTLSVersion != 0 {
+		tlsSettings = minTLSVersionSettings{TLSSettings: tlsSettings, version: o.minTLSVersion}
+	}
 
 	return &CertificateManager{
 		Locator:          certnames.MakeLocator(certsDir),
@@ -109,6 +112,10 @@ type cmOptions struct {
 	// tenantIdentifier, if set, specifies the tenant to use for loading tenant
 	// client certs.
 	tenantIdentifier uint64
+
+	// minTLSVersion, if set, overrides the minimum TLS version of the TLS
+	// configs.
+	minTLSVersion uint16
 }
 
 // Option is an option to NewCertificateManager.
@@ -123,6 +130,15 @@ func ForTenant(tenantIdentifier uint64) Option {
 	}
 }
 
+// WithMinTLSVersion is an option to NewCertificateManager which sets the
+// minimum TLS version, as returned by ParseTLSVersion, accepted by the server
+// and client TLS configs of the manager.
+func WithMinTLSVersion(version uint16) Option {
+	return func(opts *cmOptions) {
+		opts.minTLSVersion = version
+	}
+}
+
 // NewCertificateManager creates a new certificate manager.
 func NewCertificateManager(
 	certsDir string, tlsSettings TLSSettings, opts ...Option,
@@ -130,6 +130,19 @@ func newBaseTLSConfig(settings TLSSettings, caPEM []byte) (*tls.Config, error) {
 
 		CipherSuites: RecommendedCipherSuites(),
 
-		MinVersion: tls.VersionTLS12,
+		MinVersion: settings.minTLSVersion(),
 	}, nil
 }
+
+// ParseTLSVersion parses a minimum TLS protocol version, either "1.2" or
+// "1.3". The empty string stands for the default minimum, TLS 1.2.
+func ParseTLSVersion(version string) (uint16, error) {
+	switch version {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, errors.Newf("unsupported TLS version %q; supported versions are 1.2 and 1.3", version)
+	}
+}
@@ -11,6 +11,7 @@
 package security
 
 import (
+	"crypto/tls"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/settings"
@@ -38,6 +39,7 @@ type TLSSettings interface {
 	ocspStrict() bool
 	ocspTimeout() time.Duration
 	oldCipherSuitesEnabled() bool
+	minTLSVersion() uint16
 }
 
 var ocspMode = settings.RegisterEnumSetting(
@@ -77,6 +79,10 @@ func (c clusterTLSSettings) oldCipherSuitesEnabled() bool {
 	return areOldCipherSuitesEnabled()
 }
 
+func (c clusterTLSSettings) minTLSVersion() uint16 {
+	return tls.VersionTLS12
+}
+
 // ClusterTLSSettings creates a TLSSettings backed by the
 // given cluster settings.
 func ClusterTLSSettings(settings *cluster.Settings) TLSSettings {
@@ -105,6 +111,21 @@ func (c CommandTLSSettings) oldCipherSuitesEnabled() bool {
 	return areOldCipherSuitesEnabled()
 }
 
+func (CommandTLSSettings) minTLSVersion() uint16 {
+	return tls.VersionTLS12
+}
+
+// minTLSVersionSettings overrides the minimum TLS version of the wrapped
+// TLSSettings; see WithMinTLSVersion.
+type minTLSVersionSettings struct {
+	TLSSettings
+	version uint16
+}
+
+func (s minTLSVersionSettings) minTLSVersion() uint16 {
+	return s.version
+}
+
 // areOldCipherSuites returns true if CRDB should enable the use of
 // old, no longer recommended TLS cipher suites for the sake of
 // compatibility.
@@ -99,6 +99,45 @@ func TestLoadTLSConfigWithOldCipherSuites(t *testing.T) {
 	require.Equal(t, recommendedAndOldCipherSuites, clientConfig.CipherSuites)
 }
 
+func TestLoadTLSConfigWithMinTLSVersion(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	_, err := security.ParseTLSVersion("1.1")
+	require.ErrorContains(t, err, `unsupported TLS version "1.1"`)
+
+	for _, tc := range []struct {
+		version  string
+		expected uint16
+	}{
+		{"", tls.VersionTLS12},
+		{"1.2", tls.VersionTLS12},
+		{"1.3", tls.VersionTLS13},
+	} {
+		t.Run(tc.version, func(t *testing.T) {
+			version, err := security.ParseTLSVersion(tc.version)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, version)
+
+			cm, err := security.NewCertificateManager(certnames.EmbeddedCertsDir,
+				security.CommandTLSSettings{}, security.WithMinTLSVersion(version))
+			require.NoError(t, err)
+
+			for _, getConfig := range []func() (*tls.Config, error){
+				cm.GetServerTLSConfig, cm.GetUIServerTLSConfig,
+			} {
+				config, err := getConfig()
+				require.NoError(t, err)
+				clientConfig, err := config.GetConfigForClient(&tls.ClientHelloInfo{})
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, clientConfig.MinVersion)
+			}
+			config, err := cm.GetNodeClientTLSConfig()
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, config.MinVersion)
+		})
+	}
+}
+
 func verifyX509Cert(cert *x509.Certificate, dnsName string, roots *x509.CertPool) error {
 	verifyOptions := x509.VerifyOptions{
 		DNSName: dnsName,
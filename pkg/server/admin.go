@@ -2163,6 +2163,12 @@ func (s *systemAdminServer) checkReadinessForHealthCheck(ctx context.Context) er
 		return grpcstatus.Errorf(codes.Unavailable, "node is not accepting SQL clients")
 	}
 
+	for _, check := range s.server.cfg.ReadinessChecks() {
+		if err := check.Check(ctx, s.server); err != nil {
+			return grpcstatus.Errorf(codes.Unavailable, "node is not ready: %s: %v", check.Name, err)
+		}
+	}
+
 	return nil
 }
 
@@ -15,7 +15,11 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/url"
+	"os"
+	"path/filepath"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"sync/atomic"
@@ -24,6 +28,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/base/serverident"
+	"github.com/cockroachdb/cockroach/pkg/build"
 	"github.com/cockroachdb/cockroach/pkg/cloud"
 	"github.com/cockroachdb/cockroach/pkg/config/zonepb"
 	"github.com/cockroachdb/cockroach/pkg/docs"
@@ -33,8 +38,14 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/multitenant/tenantcapabilities"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/rpc"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/security/certnames"
+	"github.com/cockroachdb/cockroach/pkg/security/clientsecopts"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/server/pgurl"
 	"github.com/cockroachdb/cockroach/pkg/server/status"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/storage"
 	"github.com/cockroachdb/cockroach/pkg/storage/disk"
@@ -42,18 +53,25 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/storage/fs"
 	"github.com/cockroachdb/cockroach/pkg/ts"
 	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
 	"github.com/cockroachdb/cockroach/pkg/util/netutil"
 	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/errors/oserror"
+	"github.com/cockroachdb/logtags"
 	"github.com/cockroachdb/pebble"
 	"github.com/cockroachdb/pebble/bloom"
 	"github.com/cockroachdb/pebble/vfs"
+	"github.com/cockroachdb/pebble/vfs/errorfs"
 	"github.com/cockroachdb/redact"
+	"github.com/robfig/cron/v3"
 )
 
 // Context defaults.
@@ -70,6 +88,18 @@ const (
 	defaultScanMinIdleTime   = 10 * time.Millisecond
 	defaultScanMaxIdleTime   = 1 * time.Second
 
+	// The bounds of RecommendedScanInterval, and the share of it per range.
+	// With recommendedScanIntervalPerRange, a store with 10,000 ranges is
+	// recommended the defaultScanInterval.
+	minRecommendedScanInterval      = time.Minute
+	maxRecommendedScanInterval      = 24 * time.Hour
+	recommendedScanIntervalPerRange = defaultScanInterval / 10000
+
+	// startupMemoryOverhead is a rough estimate of the memory used by a server
+	// beyond its configured budgets (Go runtime, RPC and gossip state, range
+	// metadata, etc.). It is used by EstimatedStartupMemory.
+	startupMemoryOverhead = 256 << 20 // 256 MiB
+
 	DefaultStorePath = "cockroach-data"
 	// TempDirPrefix is the filename prefix of any temporary subdirectory
 	// created.
@@ -81,6 +111,9 @@ const (
 
 	maximumMaxClockOffset = 5 * time.Second
 
+	// defaultHTTPRequestTimeout is the default for HTTPRequestTimeout.
+	defaultHTTPRequestTimeout = 5 * time.Minute
+
 	// toleratedOffsetMultiplier is the MaxOffset multiplier used for
 	// ToleratedOffset, which determines the tolerated clock skew between this
 	// node and the cluster before self-terminating. It is conservatively set to
@@ -283,6 +316,55 @@ type BaseConfig struct {
 
 	// DiskMonitorManager provides metrics for individual disks.
 	DiskMonitorManager *disk.MonitorManager
+
+	// AuditLogDir, if set, is the directory that the SQL audit log (the
+	// SENSITIVE_ACCESS channel) is written to instead of the main log
+	// directory.
+	AuditLogDir string
+
+	// AuditLogMaxSize, if positive, is the approximate maximum size of an
+	// individual SQL audit log file before it is rotated.
+	AuditLogMaxSize int64
+
+	// AuditLogMaxFiles, if positive, bounds the number of SQL audit log
+	// files of AuditLogMaxSize that are retained; older files are removed.
+	// It requires AuditLogMaxSize to be set.
+	AuditLogMaxFiles int
+
+	// HTTPRequestTimeout bounds the time spent serving a request to the admin
+	// and status HTTP APIs. Zero disables the timeout.
+	HTTPRequestTimeout time.Duration
+
+	// RPCServerConcurrency, if positive, is the number of goroutines the gRPC
+	// server uses to process incoming streams. Zero leaves gRPC to spawn a
+	// goroutine per stream.
+	RPCServerConcurrency int
+
+	// InMemoryStoreMemoryFraction, if positive, is the fraction of the memory
+	// available to the process which the sizes of the in-memory stores may add
+	// up to before CreateEngines warns about them. Zero uses
+	// defaultInMemoryStoreMemoryFraction.
+	InMemoryStoreMemoryFraction float64
+
+	// StrictInMemoryStoreSize makes CreateEngines fail, rather than warn, when
+	// the in-memory stores exceed InMemoryStoreMemoryFraction of the memory.
+	StrictInMemoryStoreSize bool
+
+	// PGURLOptions are connection parameters, e.g. connect_timeout or
+	// application_name, appended to the query of the URLs returned by PGURL
+	// and its variants. They cannot override the parameters set by PGURL
+	// itself, nor set any of the ssl* security parameters.
+	PGURLOptions url.Values
+
+	// PGSSLMode, if set, is the libpq sslmode of the URLs returned by PGURL
+	// and its variants, e.g. verify-ca for deployments behind a load balancer
+	// whose address does not match the server certificate. By default, the
+	// mode is verify-full for secure servers and disable for insecure ones.
+	PGSSLMode string
+
+	// warnings accumulates the advisory configuration warnings logged
+	// during initialization, in order. See Warnings.
+	warnings []string
 }
 
 // MakeBaseConfig returns a BaseConfig with default values.
@@ -321,6 +403,7 @@ func (cfg *BaseConfig) SetDefaults(
 	cfg.StorageEngine = storage.DefaultStorageEngine
 	cfg.WALFailover = base.WALFailoverConfig{Mode: base.WALFailoverDefault}
 	cfg.TestingInsecureWebAccess = disableWebLogin
+	cfg.HTTPRequestTimeout = defaultHTTPRequestTimeout
 	cfg.Stores = base.StoreSpecList{
 		Specs: []base.StoreSpec{storeSpec},
 	}
@@ -380,6 +463,205 @@ func (cfg *BaseConfig) ToleratedOffset() time.Duration {
 	return time.Duration(toleratedOffsetMultiplier * float64(cfg.MaxOffset))
 }
 
+// FirstDiskStoreIndex returns the index in Stores.Specs of the first store
+// which is not in-memory, and false if there is no such store. Node-wide
+// files which are kept alongside the stores, like the job adoption stop file,
+// go into this store's directory.
+func (cfg *BaseConfig) FirstDiskStoreIndex() (int, bool) {
+	for i, spec := range cfg.Stores.Specs {
+		if !spec.InMemory && spec.Path != "" {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// LocalityString renders the node's locality tiers followed by its
+// attributes, both in the order in which they were declared, e.g.
+// "region=us-east1,zone=a,ssd". It is the canonical rendering for logs and
+// the UI.
+func (cfg *Config) LocalityString() string {
+	parts := make([]string, 0, len(cfg.Locality.Tiers))
+	for _, tier := range cfg.Locality.Tiers {
+		parts = append(parts, tier.String())
+	}
+	parts = append(parts, splitAttributes(cfg.Attrs)...)
+	return strings.Join(parts, ",")
+}
+
+// ValidateJoinDiversity returns advisory warnings about the failure domains
+// spanned by this node and the peers it knows of, i.e. the nodes whose
+// descriptors reached it through gossip after joining the cluster. When every
+// node shares this node's locality, replicas cannot be spread across failure
+// domains and the cluster cannot survive the loss of that locality. It is best
+// effort: peers without a locality are ignored, and nothing is reported when
+// this node has no locality or no peer locality is known yet.
+func (cfg *Config) ValidateJoinDiversity() []string {
+	if cfg.Locality.Empty() || cfg.peerLocalities == nil {
+		return nil
+	}
+	var known int
+	for _, peer := range cfg.peerLocalities() {
+		if peer.Empty() {
+			continue
+		}
+		if cfg.Locality.DiversityScore(peer) > 0 {
+			return nil
+		}
+		known++
+	}
+	if known == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf(
+		"this node and all %d known peers are in locality %s; "+
+			"the cluster cannot tolerate the failure of that locality",
+		known, cfg.Locality)}
+}
+
+// ValidateFilesystems returns an error if the directory of any on-disk store
+// resides on a filesystem whose type, as listed in /proc/mounts, is not one of
+// the allowed types (e.g. "ext4" or "xfs"). This lets deployments with strict
+// storage policies refuse to run on, e.g., tmpfs or NFS. It is only supported
+// on Linux.
+func (cfg *Config) ValidateFilesystems(allowed []string) error {
+	return cfg.validateFilesystems(allowed, func() ([]byte, error) {
+		return os.ReadFile("/proc/mounts")
+	})
+}
+
+func (cfg *Config) validateFilesystems(
+	allowed []string, readMounts func() ([]byte, error),
+) error {
+	mounts, err := readMounts()
+	if err != nil {
+		return errors.Wrap(err, "reading the list of mounted filesystems")
+	}
+	for _, spec := range cfg.Stores.Specs {
+		if spec.InMemory {
+			continue
+		}
+		path, err := filepath.Abs(spec.Path)
+		if err != nil {
+			return errors.Wrapf(err, "resolving store path %q", spec.Path)
+		}
+		// The store may be a symlink to another filesystem. A store directory
+		// which does not exist yet is checked as is.
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			path = resolved
+		} else if !oserror.IsNotExist(err) {
+			return errors.Wrapf(err, "resolving store path %q", spec.Path)
+		}
+		fsType, ok := mountedFilesystemType(mounts, path)
+		if !ok {
+			return errors.Newf("cannot determine the filesystem of store %s", path)
+		}
+		if !slices.Contains(allowed, fsType) {
+			return errors.WithHintf(
+				errors.Newf("store %s resides on a %s filesystem", path, fsType),
+				"Allowed filesystems: %s.", strings.Join(allowed, ", "))
+		}
+	}
+	return nil
+}
+
+// mountedFilesystemType returns the type of the filesystem containing path,
+// given the contents of /proc/mounts. The longest mount point containing path
+// wins. Mounts may be stacked on the same mount point, in which case the last
+// listed one wins.
+func mountedFilesystemType(mounts []byte, path string) (fsType string, ok bool) {
+	longest := -1
+	for _, line := range strings.Split(string(mounts), "\n") {
+		// Each line is "<device> <mount point> <type> <options> <dump> <pass>".
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		mountPoint := mountFieldEscapes.Replace(fields[1])
+		if rel, err := filepath.Rel(mountPoint, path); err == nil &&
+			rel != ".." && !strings.HasPrefix(rel, "../") && len(mountPoint) >= longest {
+			fsType, ok = fields[2], true
+			longest = len(mountPoint)
+		}
+	}
+	return fsType, ok
+}
+
+// mountFieldEscapes undoes the octal escaping that the kernel applies to
+// whitespace and backslashes in the fields of /proc/mounts.
+var mountFieldEscapes = strings.NewReplacer(`\040`, " ", `\011`, "\t", `\012`, "\n", `\134`, `\`)
+
+// SystemRangeReplicationFactor returns the replication factor which the
+// system ranges are configured with when the cluster is bootstrapped. This is
+// the replica count of DefaultSystemZoneConfig, or of the built-in default
+// system zone config if it does not specify one. Preflight checks can compare
+// it against the number of nodes or localities available.
+func (cfg *KVConfig) SystemRangeReplicationFactor() int {
+	if n := cfg.DefaultSystemZoneConfig.NumReplicas; n != nil {
+		return int(*n)
+	}
+	return int(*zonepb.DefaultSystemZoneConfig().NumReplicas)
+}
+
+// RecommendedScanInterval returns a ScanInterval suitable for a store with the
+// given number of ranges. The interval grows linearly with the range count, so
+// that small stores aren't scanned wastefully often while large ones are given
+// the time the scanner needs to visit every range, in particular no less than
+// ScanMinIdleTime per range. It is bounded by minRecommendedScanInterval and
+// maxRecommendedScanInterval.
+func (cfg *KVConfig) RecommendedScanInterval(rangeCount int) time.Duration {
+	perRange := recommendedScanIntervalPerRange
+	if cfg.ScanMinIdleTime > perRange {
+		perRange = cfg.ScanMinIdleTime
+	}
+	if rangeCount <= 0 {
+		return minRecommendedScanInterval
+	}
+	if int64(rangeCount) > int64(maxRecommendedScanInterval/perRange) {
+		return maxRecommendedScanInterval
+	}
+	interval := time.Duration(rangeCount) * perRange
+	if interval < minRecommendedScanInterval {
+		return minRecommendedScanInterval
+	}
+	return interval
+}
+
+// AdmissionConfig holds the initial values of the admission control
+// settings which bound the CPU share of elastic work (e.g. backups and
+// changefeeds), so as to protect foreground traffic. Zero values leave the
+// corresponding settings at their built-in defaults.
+type AdmissionConfig struct {
+	// ElasticCPUMinUtilization seeds admission.elastic_cpu.min_utilization.
+	ElasticCPUMinUtilization float64
+	// ElasticCPUMaxUtilization seeds admission.elastic_cpu.max_utilization.
+	ElasticCPUMaxUtilization float64
+}
+
+// validate checks that the configured utilizations are within the ranges
+// allowed by their settings, and that the floor does not exceed the ceiling
+// once the defaults are filled in.
+func (c AdmissionConfig) validate() error {
+	minUtil, maxUtil := admission.ElasticCPUMinUtilization.Default(), admission.ElasticCPUMaxUtilization.Default()
+	if c.ElasticCPUMinUtilization != 0 {
+		if err := admission.ElasticCPUMinUtilization.Validate(c.ElasticCPUMinUtilization); err != nil {
+			return errors.Wrap(err, "invalid elastic CPU min utilization")
+		}
+		minUtil = c.ElasticCPUMinUtilization
+	}
+	if c.ElasticCPUMaxUtilization != 0 {
+		if err := admission.ElasticCPUMaxUtilization.Validate(c.ElasticCPUMaxUtilization); err != nil {
+			return errors.Wrap(err, "invalid elastic CPU max utilization")
+		}
+		maxUtil = c.ElasticCPUMaxUtilization
+	}
+	if minUtil > maxUtil {
+		return errors.Errorf("elastic CPU min utilization (%.2f) must not exceed max utilization (%.2f)",
+			minUtil, maxUtil)
+	}
+	return nil
+}
+
 // Config holds the parameters needed to set up a combined KV and SQL server.
 type Config struct {
 	BaseConfig
@@ -407,6 +689,11 @@ type KVConfig struct {
 	// to A/AAAA records.
 	JoinPreferSRVRecords bool
 
+	// PrewarmDNS, if set, causes InitNode to resolve the host names in
+	// JoinList in the background, so that slow DNS servers don't stall the
+	// first gossip bootstrap attempt when the system caches DNS results.
+	PrewarmDNS bool
+
 	// RetryOptions controls the retry behavior of the server.
 	//
 	// TODO(tbg): this is only ever used in one test. Make it a testing knob.
@@ -429,6 +716,12 @@ type KVConfig struct {
 	// to find bootstrap nodes for connecting to the gossip network.
 	GossipBootstrapAddresses []util.UnresolvedAddr
 
+	// peerLocalities, if set, returns the localities currently known for the
+	// other nodes of the cluster. It is used by ValidateJoinDiversity, and set
+	// by the server to read the node descriptors gossiped by the nodes it
+	// joined.
+	peerLocalities func() []roachpb.Locality
+
 	// The following values can only be set via environment variables and are
 	// for testing only. They are not meant to be set by the end user.
 
@@ -449,11 +742,35 @@ type KVConfig struct {
 	// Environment Variable: COCKROACH_SCAN_MAX_IDLE_TIME
 	ScanMaxIdleTime time.Duration
 
+	// PrioritizeUnderReplicated, if set, makes the scanner revisit the ranges
+	// it finds to be under-replicated between the other ranges, instead of once
+	// per ScanInterval, so that their up-replication is not delayed by routine
+	// scanning, e.g. after a node failure.
+	PrioritizeUnderReplicated bool
+
+	// PreferLocalLeases, if set, biases this node toward keeping the leases of
+	// ranges whose lease preferences its locality satisfies: once it holds
+	// such a lease, it is not transferred away to balance lease counts or
+	// follow the workload. Lease preferences themselves are still enforced.
+	PreferLocalLeases bool
+
+	// ExpectedClusterSize, if positive, is the number of nodes the cluster is
+	// expected to have. Gossip sizes its peer connections for at least that
+	// many nodes from startup, instead of growing them as it discovers nodes.
+	ExpectedClusterSize int
+
 	// DefaultSystemZoneConfig is used to set the default system zone config
 	// inside the server. It can be overridden during tests by setting the
 	// DefaultSystemZoneConfigOverride server testing knob.
 	DefaultSystemZoneConfig zonepb.ZoneConfig
 
+	// DefaultGCTTL, if positive, is the GC TTL of the default zone
+	// configuration, which bounds how far back in time historical reads (AS
+	// OF SYSTEM TIME) may go in zones which do not override it. It is applied
+	// when the cluster is first initialized, on top of DefaultZoneConfig.
+	// Zero leaves the built-in default.
+	DefaultGCTTL time.Duration
+
 	// EventLogEnabled is a switch which enables recording into cockroach's SQL
 	// event log tables. These tables record transactional events about changes
 	// to cluster metadata, such as DDL statements and range rebalancing
@@ -465,6 +782,18 @@ type KVConfig struct {
 	DelayedBootstrapFn func()
 
 	enginesCreated bool
+	// engineOptions records, for each store in Stores, the rendered options its
+	// engine was opened with. It is populated by CreateEngines.
+	engineOptions []string
+	// provisionedBandwidthShares records, for each store in Stores, its share
+	// of TotalProvisionedBandwidth, or zero if it has none. It is populated by
+	// CreateEngines and applied by admissionStoreSpecs.
+	provisionedBandwidthShares []int64
+	// storeFailures holds, for each store in Stores, the injector through
+	// which SimulateStoreFailure fails its filesystem, if any. It is
+	// populated by CreateEngines when TestingKnobs.StoreFailureInjection is
+	// set.
+	storeFailures []*errorfs.Toggle
 
 	// SnapshotSendLimit is the number of concurrent snapshots a store will send.
 	SnapshotSendLimit int64
@@ -478,6 +807,47 @@ type KVConfig struct {
 	// not sent until the receiver is ready to apply, so the cost of sending is
 	// low until the receiver is ready.
 	SnapshotApplyLimit int64
+
+	// TotalProvisionedBandwidth is the disk bandwidth in bytes/s provisioned
+	// for the node as a whole. When set, the part of it not claimed by stores
+	// with an explicit provisioned-rate, which keep their own value, is
+	// divided among the other on-disk stores in proportion to their capacity
+	// and used as each store's provisioned bandwidth for admission control.
+	// This bounds the aggregate background IO of stores sharing a disk
+	// controller or network link. Zero disables the node-level budget.
+	//
+	// Pebble has no compaction rate limiter of its own to configure; the
+	// bandwidth compactions may use is paced by admission control through the
+	// provisioned bandwidth, which also covers other background IO such as
+	// snapshots. The field is named after the knob it sets rather than after
+	// compactions alone.
+	TotalProvisionedBandwidth int64
+
+	// QueueConcurrency overrides the number of replicas each of the named
+	// queues processes concurrently on every store, keyed by queue name (see
+	// kvserver.ConfigurableQueueNames). Queues not present keep their default
+	// concurrency.
+	QueueConcurrency map[string]int
+
+	// MinStoresToStart, if positive, is the number of stores which must be
+	// initialized before the node reports itself as ready to serve traffic
+	// (see ReadinessChecks). It cannot exceed the number of configured stores.
+	MinStoresToStart int
+
+	// DefaultMaxIntentsBytes, if positive, seeds the
+	// kv.transaction.max_intents_bytes cluster setting when the cluster is
+	// first initialized. Zero leaves the setting at its built-in default.
+	DefaultMaxIntentsBytes int64
+
+	// JoinRebalanceRate, if positive, limits the rate in replicas per second
+	// at which replicas are moved onto a store which joins the cluster. The
+	// allowance of a joining store grows from zero as time passes, so that it
+	// isn't flooded with replicas. Zero leaves rebalancing unlimited.
+	JoinRebalanceRate float64
+
+	// DefaultAdmissionConfig seeds admission control cluster settings when the
+	// cluster is first initialized.
+	DefaultAdmissionConfig AdmissionConfig
 }
 
 // MakeKVConfig returns a KVConfig with default values.
@@ -535,6 +905,62 @@ type SQLConfig struct {
 	// It only applies when in a shared-process configuration.
 	TenantLoopbackAddr string
 
+	// DefaultHashShardedBuckets, if positive, is the bucket count with which
+	// the primary keys of new tables are hash sharded by default. It seeds the
+	// sql.schema.default_primary_key_hash_shard_bucket_count cluster setting
+	// when the cluster is first initialized. Zero leaves the setting untouched.
+	DefaultHashShardedBuckets int
+
+	// MaxSessionResultBytes, if positive, is the default amount of memory in
+	// bytes which a session may use to buffer statement results before they
+	// are sent to the client. It seeds the sql.defaults.results_buffer.size
+	// cluster setting when the cluster is first initialized; individual
+	// connections can still override it with the results_buffer_size
+	// parameter. Zero leaves the setting at its default.
+	MaxSessionResultBytes int64
+
+	// DescriptorLeaseDuration, if positive, is the mean duration of SQL
+	// descriptor leases. It seeds the sql.catalog.descriptor_lease_duration
+	// cluster setting when the cluster is first initialized. Zero leaves the
+	// setting at its default.
+	DescriptorLeaseDuration time.Duration
+
+	// DefaultDistSQLMode, if set, is the default distributed SQL execution
+	// mode: one of "off", "auto" or "on". It seeds the sql.defaults.distsql
+	// cluster setting when the cluster is first initialized. Empty leaves the
+	// setting at its default.
+	DefaultDistSQLMode string
+
+	// SlowQueryLogThreshold, if positive, is the service latency above which
+	// SQL statements are logged to the slow query log. It seeds the
+	// sql.log.slow_query.latency_threshold cluster setting when the cluster is
+	// first initialized. Zero leaves slow query logging disabled.
+	SlowQueryLogThreshold time.Duration
+
+	// AutoStatsMinStaleRows, if positive, is the minimum number of stale rows
+	// which trigger an automatic statistics refresh of a table. It seeds the
+	// sql.stats.automatic_collection.min_stale_rows cluster setting when the
+	// cluster is first initialized. Zero leaves the setting at its default.
+	AutoStatsMinStaleRows int64
+
+	// DefaultTimeZone, if set, is the name of the IANA time zone, e.g.
+	// "America/New_York", which new sessions use by default. It is applied as
+	// a default of the timezone session variable for all roles when the
+	// cluster is first initialized, and individual sessions can still override
+	// it. Empty leaves the default at UTC.
+	DefaultTimeZone string
+
+	// BootstrapBackupSchedule, if set, is the cron expression, e.g. "@daily",
+	// of a schedule of backups of the cluster into BootstrapBackupURI, which
+	// is created when the cluster is first initialized. Creating the schedule
+	// requires a CCL binary, so OSS binaries reject the option.
+	BootstrapBackupSchedule string
+
+	// BootstrapBackupURI is the external storage URI into which the backups
+	// of BootstrapBackupSchedule are written. It is sanitized in logs, as it
+	// may embed credentials.
+	BootstrapBackupURI string
+
 	// The following values can only be set via environment variables and are
 	// for testing only. They are not meant to be set by the end user.
 
@@ -665,6 +1091,15 @@ func (cfg *Config) String() string {
 	fmt.Fprintln(w, "scan interval\t", cfg.ScanInterval)
 	fmt.Fprintln(w, "scan min idle time\t", cfg.ScanMinIdleTime)
 	fmt.Fprintln(w, "scan max idle time\t", cfg.ScanMaxIdleTime)
+	if cfg.PrioritizeUnderReplicated {
+		fmt.Fprintln(w, "prioritize under-replicated\t", cfg.PrioritizeUnderReplicated)
+	}
+	if cfg.PreferLocalLeases {
+		fmt.Fprintln(w, "prefer local leases\t", cfg.PreferLocalLeases)
+	}
+	if cfg.ExpectedClusterSize > 0 {
+		fmt.Fprintln(w, "expected cluster size\t", cfg.ExpectedClusterSize)
+	}
 	fmt.Fprintln(w, "event log enabled\t", cfg.EventLogEnabled)
 	if cfg.Linearizable {
 		fmt.Fprintln(w, "linearizable\t", cfg.Linearizable)
@@ -705,6 +1140,35 @@ func (e *Engines) Close() {
 	*e = nil
 }
 
+// EstimatedStartupMemory returns an estimate, in bytes, of the memory the
+// server will use once its engines are open and it starts serving SQL. It
+// sums the shared block cache, the caches dedicated to a store, the memtable
+// budget of every store, the contents of in-memory stores of a fixed size, the
+// SQL memory pool and a fixed overhead. It does not open anything, and can be
+// used to refuse to start on a host without enough memory.
+func (cfg *Config) EstimatedStartupMemory() uint64 {
+	total := uint64(cfg.CacheSize) + uint64(cfg.MemoryPoolSize) + startupMemoryOverhead
+	for _, spec := range cfg.Stores.Specs {
+		opts := storage.DefaultPebbleOptions()
+		if spec.PebbleOptions != "" {
+			// An invalid option string is reported by CreateEngines; fall back
+			// to the defaults for the purpose of the estimate.
+			_ = opts.Parse(spec.PebbleOptions, &pebble.ParseHooks{})
+		}
+		total += opts.MemTableSize * uint64(opts.MemTableStopWritesThreshold)
+		total += uint64(spec.CacheSize)
+		if spec.InMemory {
+			total += uint64(spec.Size.InBytes)
+		}
+	}
+	return total
+}
+
+// totalMemory returns the memory available to the process, clamped to the
+// cgroup limit if any. In-memory stores sized as a percentage are resolved
+// against it. It is a variable so that tests can mock the memory source.
+var totalMemory = status.GetTotalMemory
+
 // CreateEngines creates Engines based on the specs in cfg.Stores.
 func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 	var engines Engines
@@ -715,6 +1179,16 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 	}
 	cfg.enginesCreated = true
 
+	if err := cfg.validateTotalProvisionedBandwidth(); err != nil {
+		return Engines{}, err
+	}
+	if err := cfg.validateStorePaths(); err != nil {
+		return Engines{}, err
+	}
+	if err := cfg.validateInMemoryStoreSizes(ctx); err != nil {
+		return Engines{}, err
+	}
+
 	var details []redact.RedactableString
 	detail := func(msg redact.RedactableString) {
 		details = append(details, msg)
@@ -736,10 +1210,13 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 		}
 	}
 
-	var physicalStores int
+	var physicalStores, sharedCacheStores int
 	for _, spec := range cfg.Stores.Specs {
 		if !spec.InMemory {
 			physicalStores++
+			if spec.CacheSize == 0 {
+				sharedCacheStores++
+			}
 		}
 	}
 	openFileLimitPerStore, err := setOpenFileLimit(physicalStores)
@@ -751,9 +1228,11 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 
 	var tableCache *pebble.TableCache
 	// TODO(radu): use the tableCache for in-memory stores as well.
-	if physicalStores > 0 {
+	if sharedCacheStores > 0 {
+		// Stores with a cache of their own can't share the table cache, which
+		// is tied to the block cache; Pebble creates one for each of them.
 		perStoreLimit := pebble.TableCacheSize(int(openFileLimitPerStore))
-		totalFileLimit := perStoreLimit * physicalStores
+		totalFileLimit := perStoreLimit * sharedCacheStores
 		tableCache = pebble.NewTableCache(pebbleCache, runtime.GOMAXPROCS(0), totalFileLimit)
 	}
 
@@ -772,9 +1251,17 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 		return Engines{}, err
 	}
 	defer storeEnvs.CloseAll()
+	if serverKnobs, ok := cfg.TestingKnobs.Server.(*TestingKnobs); ok && serverKnobs.StoreFailureInjection {
+		if err := cfg.wrapStoreEnvsForFailures(ctx, storeEnvs, stickyRegistry); err != nil {
+			return Engines{}, err
+		}
+	}
 
 	walFailoverConfig := storage.WALFailover(cfg.WALFailover, storeEnvs, vfs.Default, cfg.DiskWriteStatsCollector)
 
+	// storeCapacities records the capacity of each on-disk store, used to
+	// divide TotalProvisionedBandwidth among them.
+	storeCapacities := make([]int64, len(cfg.Stores.Specs))
 	for i, spec := range cfg.Stores.Specs {
 		log.Eventf(ctx, "initializing %+v", spec)
 
@@ -790,22 +1277,50 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 		addCfgOpt := func(opt storage.ConfigOption) {
 			storageConfigOpts = append(storageConfigOpts, opt)
 		}
+		if spec.MaxWALSize > 0 {
+			addCfgOpt(storage.MaxWALSize(spec.MaxWALSize))
+		}
+		if spec.BytesPerSync > 0 {
+			addCfgOpt(storage.BytesPerSync(spec.BytesPerSync))
+		}
+		if spec.WALBytesPerSync > 0 {
+			addCfgOpt(storage.WALBytesPerSync(spec.WALBytesPerSync))
+		}
+		if spec.MaxBackgroundJobs > 0 {
+			addCfgOpt(storage.MaxBackgroundJobs(spec.MaxBackgroundJobs))
+		} else if n := len(cfg.Stores.Specs); n > 1 {
+			// The stores share the CPUs for their flushes and compactions.
+			addCfgOpt(storage.StoreCount(n))
+		}
+		if spec.L0CompactionThreshold > 0 {
+			addCfgOpt(storage.L0CompactionThreshold(spec.L0CompactionThreshold))
+		}
 
 		if spec.InMemory {
 			var sizeInBytes = spec.Size.InBytes
 			if spec.Size.Percent > 0 {
-				sysMem, err := status.GetTotalMemory(ctx)
+				sysMem, err := totalMemory(ctx)
 				if err != nil {
 					return Engines{}, errors.Errorf("could not retrieve system memory")
 				}
 				sizeInBytes = int64(float64(sysMem) * spec.Size.Percent / 100)
+				if sizeInBytes == 0 {
+					// A zero size would leave the store unbounded, which is not
+					// what a percentage was asking for.
+					return Engines{}, errors.Errorf("%f%% of %s of memory rounds to zero bytes",
+						spec.Size.Percent, humanizeutil.IBytes(sysMem))
+				}
 			}
 			if sizeInBytes != 0 && !storeKnobs.SkipMinSizeCheck && sizeInBytes < base.MinimumStoreSize {
 				return Engines{}, errors.Errorf("%f%% of memory is only %s bytes, which is below the minimum requirement of %s",
 					spec.Size.Percent, humanizeutil.IBytes(sizeInBytes), humanizeutil.IBytes(base.MinimumStoreSize))
 			}
 			addCfgOpt(storage.MaxSize(sizeInBytes))
-			addCfgOpt(storage.CacheSize(cfg.CacheSize))
+			if spec.CacheSize > 0 {
+				addCfgOpt(storage.CacheSize(spec.CacheSize))
+			} else {
+				addCfgOpt(storage.CacheSize(cfg.CacheSize))
+			}
 			addCfgOpt(storage.RemoteStorageFactory(cfg.EarlyBootExternalStorageAccessor))
 
 			detail(redact.Sprintf("store %d: in-memory, size %s", i, humanizeutil.IBytes(sizeInBytes)))
@@ -832,9 +1347,21 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 
 			detail(redact.Sprintf("store %d: max size %s, max open file limit %d", i, humanizeutil.IBytes(sizeInBytes), openFileLimitPerStore))
 
+			storeCapacities[i] = sizeInBytes
+			if storeCapacities[i] == 0 {
+				storeCapacities[i] = int64(du.TotalBytes)
+			}
+
 			addCfgOpt(storage.MaxSize(sizeInBytes))
 			addCfgOpt(storage.BallastSize(storage.BallastSizeBytes(spec, du)))
-			addCfgOpt(storage.Caches(pebbleCache, tableCache))
+			if spec.CacheSize > 0 {
+				storeCache := pebble.NewCache(spec.CacheSize)
+				defer storeCache.Unref()
+				addCfgOpt(storage.Caches(storeCache, nil /* tableCache */))
+				detail(redact.Sprintf("store %d: cache size %s", i, humanizeutil.IBytes(spec.CacheSize)))
+			} else {
+				addCfgOpt(storage.Caches(pebbleCache, tableCache))
+			}
 			// TODO(radu): move up all remaining settings below so they apply to in-memory stores as well.
 			addCfgOpt(storage.MaxOpenFiles(int(openFileLimitPerStore)))
 			addCfgOpt(storage.MaxWriterConcurrency(2))
@@ -872,9 +1399,24 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 		// or leave ownership with the caller of Open.
 		storeEnvs[i] = nil
 		detail(redact.Sprintf("store %d: %s", i, eng.Properties()))
+		cfg.engineOptions = append(cfg.engineOptions, eng.OptionsString())
 		engines = append(engines, eng)
 	}
 
+	if cfg.TotalProvisionedBandwidth > 0 {
+		shares, err := divideProvisionedBandwidth(
+			cfg.TotalProvisionedBandwidth, cfg.Stores.Specs, storeCapacities)
+		if err != nil {
+			return Engines{}, err
+		}
+		for i, share := range shares {
+			if share > 0 {
+				detail(redact.Sprintf("store %d: provisioned bandwidth %s/s", i, humanizeutil.IBytes(share)))
+			}
+		}
+		cfg.provisionedBandwidthShares = shares
+	}
+
 	if tableCache != nil {
 		// Unref the table cache now that the engines hold references to it.
 		if err := tableCache.Unref(); err != nil {
@@ -894,21 +1436,1056 @@ func (cfg *Config) CreateEngines(ctx context.Context) (Engines, error) {
 	return enginesCopy, nil
 }
 
+// PebbleOptionsFor returns the options the engine of the store at the given
+// index in cfg.Stores was opened with, in the format of a Pebble OPTIONS file.
+// This format derives from RocksDB's, so the result can be compared against
+// the OPTIONS file the engine persists in the store directory. It is only
+// available once CreateEngines has been called.
+func (cfg *Config) PebbleOptionsFor(storeIndex int) (string, error) {
+	if !cfg.enginesCreated {
+		return "", errors.Errorf("engines not created yet")
+	}
+	if storeIndex < 0 || storeIndex >= len(cfg.engineOptions) {
+		return "", errors.Errorf("store index %d out of range [0, %d)",
+			storeIndex, len(cfg.engineOptions))
+	}
+	return cfg.engineOptions[storeIndex], nil
+}
+
+// SimulateStoreFailure makes every subsequent operation on the filesystem of
+// the store at the given index in cfg.Stores fail, as if its disk had
+// failed, for chaos testing. It is only available for in-memory stores, once
+// CreateEngines has been called with TestingKnobs.StoreFailureInjection set.
+func (cfg *Config) SimulateStoreFailure(storeIndex int) error {
+	if !cfg.enginesCreated {
+		return errors.Errorf("engines not created yet")
+	}
+	if storeIndex < 0 || storeIndex >= len(cfg.Stores.Specs) {
+		return errors.Errorf("store index %d out of range [0, %d)",
+			storeIndex, len(cfg.Stores.Specs))
+	}
+	if storeIndex >= len(cfg.storeFailures) || cfg.storeFailures[storeIndex] == nil {
+		return errors.Errorf("store %d does not support failure injection; "+
+			"it must be in memory and TestingKnobs.StoreFailureInjection must be set", storeIndex)
+	}
+	cfg.storeFailures[storeIndex].On()
+	return nil
+}
+
+// wrapStoreEnvsForFailures replaces the Envs of the in-memory stores with ones
+// whose filesystems can be failed through SimulateStoreFailure.
+func (cfg *Config) wrapStoreEnvsForFailures(
+	ctx context.Context, envs fs.Envs, stickyRegistry fs.StickyRegistry,
+) error {
+	cfg.storeFailures = make([]*errorfs.Toggle, len(cfg.Stores.Specs))
+	for i, spec := range cfg.Stores.Specs {
+		if !spec.InMemory {
+			continue
+		}
+		var memFS vfs.FS = vfs.NewMem()
+		if spec.StickyVFSID != "" {
+			memFS = stickyRegistry.Get(spec.StickyVFSID)
+		}
+		// Close the original Env first, to release its lock on the directory
+		// of a sticky filesystem.
+		envs[i].Close()
+		envs[i] = nil
+		toggle := &errorfs.Toggle{Injector: errorfs.ErrInjected}
+		env, err := fs.InitEnv(ctx, errorfs.Wrap(memFS, toggle), spec.Path, fs.EnvConfig{
+			RW:                fs.ReadWrite,
+			EncryptionOptions: spec.EncryptionOptions,
+		}, cfg.DiskWriteStatsCollector)
+		if err != nil {
+			return err
+		}
+		envs[i] = env
+		cfg.storeFailures[i] = toggle
+	}
+	return nil
+}
+
+// EncryptedStores returns the indices in cfg.Stores of the stores configured
+// with encryption-at-rest, in increasing order. Unlike PebbleOptionsFor, it
+// only depends on the store specs and can be called before CreateEngines.
+func (cfg *Config) EncryptedStores() []int {
+	var indices []int
+	for i, spec := range cfg.Stores.Specs {
+		if len(spec.EncryptionOptions) > 0 {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// validateTotalProvisionedBandwidth checks that the node-level provisioned
+// bandwidth is non-negative. Zero means that it is unset.
+func (cfg *KVConfig) validateTotalProvisionedBandwidth() error {
+	if cfg.TotalProvisionedBandwidth < 0 {
+		return errors.Errorf("total provisioned bandwidth must be non-negative, got %d",
+			cfg.TotalProvisionedBandwidth)
+	}
+	return nil
+}
+
+// admissionStoreSpecs returns the store specs to configure admission control
+// with, i.e. a copy of cfg.Stores.Specs in which the stores without an
+// explicit provisioned-rate use their share of TotalProvisionedBandwidth, if
+// any.
+func (cfg *Config) admissionStoreSpecs() []base.StoreSpec {
+	if len(cfg.provisionedBandwidthShares) == 0 {
+		return cfg.Stores.Specs
+	}
+	specs := append([]base.StoreSpec(nil), cfg.Stores.Specs...)
+	for i, share := range cfg.provisionedBandwidthShares {
+		if share > 0 {
+			specs[i].ProvisionedRateSpec.ProvisionedBandwidth = share
+		}
+	}
+	return specs
+}
+
+// validateStorePaths checks that no two on-disk stores share a directory, and
+// that no store directory is nested within another, which would otherwise
+// surface as confusing lock errors when opening the engines.
+func (cfg *BaseConfig) validateStorePaths() error {
+	type storePath struct {
+		spec base.StoreSpec
+		abs  string
+	}
+	var paths []storePath
+	for _, spec := range cfg.Stores.Specs {
+		if spec.InMemory {
+			continue
+		}
+		abs, err := filepath.Abs(spec.Path)
+		if err != nil {
+			return errors.Wrapf(err, "resolving store path %s", spec.Path)
+		}
+		for _, other := range paths {
+			if abs == other.abs {
+				return errors.Errorf("stores %q and %q use the same directory %s", other.spec, spec, abs)
+			}
+			if isNestedPath(other.abs, abs) || isNestedPath(abs, other.abs) {
+				return errors.Errorf("stores %q and %q have nested directories", other.spec, spec)
+			}
+		}
+		paths = append(paths, storePath{spec: spec, abs: abs})
+	}
+	return nil
+}
+
+// isNestedPath returns whether the absolute path is strictly within the
+// absolute directory dir.
+func isNestedPath(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// defaultInMemoryStoreMemoryFraction is the default value of
+// InMemoryStoreMemoryFraction. In-memory stores which add up to all of the
+// memory are bound to get the process OOM-killed once they fill up.
+const defaultInMemoryStoreMemoryFraction = 1.0
+
+// validateInMemoryStoreSizes compares the total size of the in-memory stores
+// against InMemoryStoreMemoryFraction of the memory available to the process,
+// which accounts for the cgroup limit. Exceeding it is logged as a warning, or
+// returned as an error if StrictInMemoryStoreSize is set.
+func (cfg *BaseConfig) validateInMemoryStoreSizes(ctx context.Context) error {
+	fraction := cfg.InMemoryStoreMemoryFraction
+	if fraction < 0 {
+		return errors.Errorf("in-memory store memory fraction must be non-negative, got %f", fraction)
+	}
+	if fraction == 0 {
+		fraction = defaultInMemoryStoreMemoryFraction
+	}
+	var stores int
+	var sizeBytes int64
+	var sizePercent float64
+	for _, spec := range cfg.Stores.Specs {
+		if spec.InMemory {
+			stores++
+			sizeBytes += spec.Size.InBytes
+			sizePercent += spec.Size.Percent
+		}
+	}
+	if stores == 0 {
+		return nil
+	}
+	sysMem, err := totalMemory(ctx)
+	if err != nil {
+		// CreateEngines reports the error if it needs the memory to size a
+		// store; the check is best-effort otherwise.
+		cfg.warnf(ctx, "unable to check the size of the in-memory stores: %v", err)
+		return nil
+	}
+	total := sizeBytes + int64(float64(sysMem)*sizePercent/100)
+	limit := int64(float64(sysMem) * fraction)
+	if total <= limit {
+		return nil
+	}
+	err = errors.WithHintf(
+		errors.Errorf("the %d in-memory stores add up to %s, more than %.4g%% of the %s of memory available",
+			stores, humanizeutil.IBytes(total), fraction*100, humanizeutil.IBytes(sysMem)),
+		"Reduce the size of the in-memory stores.",
+	)
+	if cfg.StrictInMemoryStoreSize {
+		return err
+	}
+	cfg.warnf(ctx, "%v", err)
+	return nil
+}
+
+// validateQueueConcurrency checks that QueueConcurrency only refers to queues
+// whose concurrency can be configured, and that each concurrency is positive.
+func (cfg *KVConfig) validateQueueConcurrency() error {
+	for name, n := range cfg.QueueConcurrency {
+		if !slices.Contains(kvserver.ConfigurableQueueNames, name) {
+			return errors.Errorf("unknown queue %q, expected one of %s",
+				name, strings.Join(kvserver.ConfigurableQueueNames, ", "))
+		}
+		if n < 1 {
+			return errors.Errorf("concurrency of queue %q must be at least 1, got %d", name, n)
+		}
+	}
+	return nil
+}
+
+// validateRaftTimeouts checks that the raft tick interval is positive and that
+// the election timeout is longer than the heartbeat interval, so that a leader
+// heartbeating at the configured rate does not see its followers call
+// elections.
+func (cfg *KVConfig) validateRaftTimeouts() error {
+	if cfg.RaftTickInterval <= 0 {
+		return errors.Errorf("raft tick interval must be positive, got %s", cfg.RaftTickInterval)
+	}
+	if cfg.RaftHeartbeatIntervalTicks < 1 {
+		return errors.Errorf("raft heartbeat interval must be at least 1 tick, got %d",
+			cfg.RaftHeartbeatIntervalTicks)
+	}
+	if cfg.RaftElectionTimeoutTicks <= cfg.RaftHeartbeatIntervalTicks {
+		return errors.Errorf("raft election timeout (%d ticks) must exceed the heartbeat interval (%d ticks)",
+			cfg.RaftElectionTimeoutTicks, cfg.RaftHeartbeatIntervalTicks)
+	}
+	return nil
+}
+
+// minRaftLogTruncationThreshold is the smallest accepted raft log truncation
+// threshold. Below it, ranges truncate their logs after a handful of
+// proposals, and followers that fall even slightly behind need snapshots to
+// catch up.
+const minRaftLogTruncationThreshold = 1 << 20 // 1 MiB
+
+// validateRaftLogTruncationThreshold checks that RaftLogTruncationThreshold,
+// if set, is at least minRaftLogTruncationThreshold. Zero uses the default.
+func (cfg *KVConfig) validateRaftLogTruncationThreshold() error {
+	if t := cfg.RaftLogTruncationThreshold; t != 0 && t < minRaftLogTruncationThreshold {
+		return errors.Errorf("raft log truncation threshold must be at least %s, got %d",
+			humanizeutil.IBytes(minRaftLogTruncationThreshold), t)
+	}
+	return nil
+}
+
+// validateDefaultMaxIntentsBytes checks that DefaultMaxIntentsBytes is not
+// negative.
+func (cfg *KVConfig) validateDefaultMaxIntentsBytes() error {
+	if cfg.DefaultMaxIntentsBytes < 0 {
+		return errors.Errorf("default max intents bytes must be non-negative, got %d",
+			cfg.DefaultMaxIntentsBytes)
+	}
+	return nil
+}
+
+// validateJoinRebalanceRate checks that JoinRebalanceRate is not negative.
+func (cfg *KVConfig) validateJoinRebalanceRate() error {
+	if cfg.JoinRebalanceRate < 0 {
+		return errors.Errorf("join rebalance rate must be non-negative, got %v", cfg.JoinRebalanceRate)
+	}
+	return nil
+}
+
+// validateExpectedClusterSize checks that ExpectedClusterSize is not negative.
+func (cfg *KVConfig) validateExpectedClusterSize() error {
+	if cfg.ExpectedClusterSize < 0 {
+		return errors.Errorf("expected cluster size must be non-negative, got %d", cfg.ExpectedClusterSize)
+	}
+	return nil
+}
+
+// maxAddressableMemory is the size of the address space of the process, which
+// is only a meaningful bound on 32-bit platforms.
+const maxAddressableMemory = uint64(^uintptr(0))
+
+// validateAddressableMemory checks that the block caches and memtables of the
+// stores, along with the rest of the startup memory estimated by
+// EstimatedStartupMemory, fit in an address space of the given size, so that
+// an over-large cache on a 32-bit build fails with a clear error rather than
+// when the engines allocate it.
+func (cfg *Config) validateAddressableMemory(addressable uint64) error {
+	if needed := cfg.EstimatedStartupMemory(); needed > addressable {
+		return errors.WithHintf(
+			errors.Errorf("the configured caches and memory pools need %s, more than the %s this process can address",
+				humanizeutil.IBytes(int64(needed)), humanizeutil.IBytes(int64(addressable))),
+			"Reduce --cache, --max-sql-memory or the store sizes.")
+	}
+	return nil
+}
+
+// validateMinStoresToStart checks that MinStoresToStart is non-negative and
+// can be satisfied by the configured stores.
+func (cfg *Config) validateMinStoresToStart() error {
+	if n := cfg.MinStoresToStart; n < 0 || n > len(cfg.Stores.Specs) {
+		return errors.Errorf("minimum stores to start must be in range [0, %d], got %d",
+			len(cfg.Stores.Specs), n)
+	}
+	return nil
+}
+
+// divideProvisionedBandwidth returns the share of total of each of the given
+// stores. The explicit provisioned rates of the stores that set one are
+// subtracted from total, and the remainder is split among the other on-disk
+// stores in proportion to their capacities, or evenly if the capacity of
+// some of them is not known. In-memory stores and stores with an explicit rate get no
+// share.
+func divideProvisionedBandwidth(
+	total int64, specs []base.StoreSpec, capacities []int64,
+) ([]int64, error) {
+	shares := make([]int64, len(specs))
+	remaining := total
+	var eligible []int
+	var sum int64
+	capacitiesKnown := true
+	for i, spec := range specs {
+		switch {
+		case spec.InMemory:
+		case spec.ProvisionedRateSpec.ProvisionedBandwidth > 0:
+			remaining -= spec.ProvisionedRateSpec.ProvisionedBandwidth
+		default:
+			eligible = append(eligible, i)
+			sum += capacities[i]
+			capacitiesKnown = capacitiesKnown && capacities[i] > 0
+		}
+	}
+	if len(eligible) == 0 {
+		return shares, nil
+	}
+	if remaining <= 0 {
+		return nil, errors.Errorf("total provisioned bandwidth %s/s leaves nothing for %d store(s) "+
+			"once the explicit provisioned rates are subtracted",
+			humanizeutil.IBytes(total), len(eligible))
+	}
+	for _, i := range eligible {
+		if capacitiesKnown {
+			shares[i] = int64(float64(remaining) * float64(capacities[i]) / float64(sum))
+		} else {
+			shares[i] = remaining / int64(len(eligible))
+		}
+		if shares[i] <= 0 {
+			return nil, errors.Errorf("total provisioned bandwidth %s/s is too small to be divided among %d store(s)",
+				humanizeutil.IBytes(total), len(eligible))
+		}
+	}
+	return shares, nil
+}
+
+// PGURL returns a SQL connection URL for the given user, in the form
+// printed by the server on startup. See PGURLStruct.
+func (cfg *BaseConfig) PGURL(user string) (string, error) {
+	u, err := cfg.PGURLStruct(user)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// PGURLStruct returns a SQL connection URL for the given user, with the SSL
+// options appropriate for the security settings of the server: sslmode=disable
+// for insecure servers, and verification of the server certificate against
+// the CA certificate in the certs directory, if any, otherwise. Callers may
+// add connection parameters, e.g. application_name, to its query.
+func (cfg *BaseConfig) PGURLStruct(user string) (*url.URL, error) {
+	return cfg.pgURL(user, "" /* database */, "" /* socketDir */, nil /* options */)
+}
+
+// PGURLForDatabase is like PGURL, but the URL connects to the given database
+// instead of the default one. An empty database behaves like PGURL.
+func (cfg *BaseConfig) PGURLForDatabase(user, database string) (string, error) {
+	u, err := cfg.pgURL(user, database, "" /* socketDir */, nil /* options */)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// PGURLForUnixSocket is like PGURL, but the URL connects through the unix
+// socket the server listens on in the given directory, as in
+// postgresql://user@/defaultdb?host=/path/to/dir&port=26257. The socket file
+// in the directory is named after the port, as expected by libpq.
+func (cfg *BaseConfig) PGURLForUnixSocket(user, socketDir string) (string, error) {
+	u, err := cfg.pgURL(user, "" /* database */, socketDir, nil /* options */)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// pgURL builds the URLs returned by the PGURL* methods. The given options,
+// if any, are added to the URL before the PGURLOptions, which cannot override
+// them.
+func (cfg *BaseConfig) pgURL(
+	user, database, socketDir string, options url.Values,
+) (*url.URL, error) {
+	clientConnOptions, serverParams := MakeServerOptionsForURL(cfg.Config)
+	if database != "" {
+		serverParams.DefaultDatabase = database
+	}
+	// An advertised address without a host, or with an unspecified one, is
+	// not one clients can connect to; point them at the local host instead.
+	// IPv6 literals are bracketed when the URL is rendered.
+	if host, port, err := net.SplitHostPort(serverParams.ServerAddr); err == nil {
+		if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+			serverParams.ServerAddr = net.JoinHostPort("localhost", port)
+		}
+	}
+	u, err := clientsecopts.MakeURLForServer(clientConnOptions, serverParams, url.User(user))
+	if err != nil {
+		return nil, err
+	}
+	if socketDir != "" {
+		_, _, port := u.GetNetworking()
+		u.WithNet(pgurl.NetUnix(socketDir, port))
+	}
+	if len(options) > 0 {
+		if err := u.AddOptions(options); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.PGSSLMode != "" {
+		if err := cfg.validatePGSSLMode(); err != nil {
+			return nil, err
+		}
+		if cfg.PGSSLMode == "disable" {
+			u.WithTransport(pgurl.TransportNone())
+		} else {
+			// Keep the CA certificate, if any, found by MakeURLForServer.
+			_, _, caCertPath := u.GetTLSOptions()
+			u.WithTransport(pgurl.TransportTLS(pgurl.TLSMode(cfg.PGSSLMode), caCertPath))
+		}
+	}
+	pq := u.ToPQ()
+	if len(cfg.PGURLOptions) > 0 {
+		query := pq.Query()
+		for key := range cfg.PGURLOptions {
+			if query.Has(key) || strings.HasPrefix(key, "ssl") {
+				return nil, errors.Errorf("PG URL option %s cannot be overridden", key)
+			}
+		}
+		// Append the options, rather than re-encoding the whole query, so
+		// that the parameters set above keep their order.
+		if pq.RawQuery != "" {
+			pq.RawQuery += "&"
+		}
+		pq.RawQuery += cfg.PGURLOptions.Encode()
+	}
+	return pq, nil
+}
+
+// PGURLForTenant is like PGURL, but the URL selects the tenant with the given
+// ID, using the -ccluster connection option understood by servers hosting
+// multiple virtual clusters.
+//
+// Tenants are selected by name. The system tenant is named "system" and other
+// tenants are assumed to use the name generated for tenants created without
+// an explicit name, "cluster-<id>".
+func (cfg *BaseConfig) PGURLForTenant(user string, tenantID uint64) (string, error) {
+	tid, err := roachpb.MakeTenantID(tenantID)
+	if err != nil {
+		return "", err
+	}
+	tenantName := catconstants.SystemTenantName
+	if !tid.IsSystem() {
+		tenantName = fmt.Sprintf("cluster-%d", tenantID)
+	}
+	options := url.Values{"options": []string{"-ccluster=" + tenantName}}
+	u, err := cfg.pgURL(user, "" /* database */, "" /* socketDir */, options)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// ProbeConfig returns a configuration holding only what a client needs to
+// connect to this server's SQL port, i.e. the security settings and the
+// advertised SQL address, e.g. for monitoring tools running health queries.
+// It can be passed to MakeServerOptionsForURL.
+func (cfg *BaseConfig) ProbeConfig() *base.Config {
+	return &base.Config{
+		Insecure:         cfg.Insecure,
+		SSLCertsDir:      cfg.SSLCertsDir,
+		SQLAdvertiseAddr: cfg.SQLAdvertiseAddr,
+	}
+}
+
 // InitSQLServer finalizes the configuration of a SQL-only node.
 // It initializes additional configuration flags from the environment.
 func (cfg *Config) InitSQLServer(ctx context.Context) error {
 	cfg.readSQLEnvironmentVariables()
+	if err := cfg.validateInsecureExposure(); err != nil {
+		return err
+	}
+	if err := cfg.validateMaxConnsPerIP(); err != nil {
+		return err
+	}
+	if err := cfg.validateMaxNewConnsPerSecond(); err != nil {
+		return err
+	}
+	if err := cfg.validatePGSSLMode(); err != nil {
+		return err
+	}
+	if err := cfg.validateDrainAllowedUsers(); err != nil {
+		return err
+	}
+	if err := cfg.validateHTTPRequestTimeout(); err != nil {
+		return err
+	}
+	if err := cfg.validateRPCServerConcurrency(); err != nil {
+		return err
+	}
+	if err := cfg.validateMinTLSVersion(); err != nil {
+		return err
+	}
+	return cfg.validateAuditLog()
+}
+
+// validateHTTPRequestTimeout checks that HTTPRequestTimeout is not negative.
+func (cfg *BaseConfig) validateHTTPRequestTimeout() error {
+	if cfg.HTTPRequestTimeout < 0 {
+		return errors.Errorf("HTTP request timeout must be non-negative, got %s", cfg.HTTPRequestTimeout)
+	}
+	return nil
+}
+
+// validateMinTLSVersion checks that MinTLSVersion names a supported TLS
+// version.
+func (cfg *BaseConfig) validateMinTLSVersion() error {
+	if _, err := security.ParseTLSVersion(cfg.MinTLSVersion); err != nil {
+		return errors.Wrap(err, "invalid minimum TLS version")
+	}
+	return nil
+}
+
+// validateRPCServerConcurrency checks that RPCServerConcurrency is not
+// negative.
+func (cfg *BaseConfig) validateRPCServerConcurrency() error {
+	if cfg.RPCServerConcurrency < 0 {
+		return errors.Errorf("RPC server concurrency must be non-negative, got %d", cfg.RPCServerConcurrency)
+	}
+	return nil
+}
+
+// CheckCertExpiry examines the certificates in the certificates directory. It
+// returns an error if any of them has expired, and logs a warning for those
+// which expire within warnWithin, so that certificate problems surface at
+// startup rather than as failures of later connections. It does nothing in
+// insecure mode.
+func (cfg *BaseConfig) CheckCertExpiry(ctx context.Context, warnWithin time.Duration) error {
+	if cfg.Insecure {
+		return nil
+	}
+	cl := security.NewCertificateLoader(cfg.SSLCertsDir)
+	if err := cl.Load(); err != nil {
+		return errors.Wrapf(err, "loading certificates from %s", cfg.SSLCertsDir)
+	}
+	now := timeutil.Now()
+	for _, ci := range cl.Certificates() {
+		if ci.Error != nil {
+			// The loader already logged the problem, and the certificate
+			// manager reports it when the certificate is used.
+			continue
+		}
+		if !ci.ExpirationTime.After(now) {
+			return errors.Errorf("%s certificate %s expired at %s",
+				ci.FileUsage, ci.Filename, ci.ExpirationTime)
+		}
+		if remaining := ci.ExpirationTime.Sub(now); remaining < warnWithin {
+			cfg.warnf(ctx, "%s certificate %s expires at %s, in %s",
+				ci.FileUsage, ci.Filename, ci.ExpirationTime, remaining.Round(time.Second))
+		}
+	}
+	return nil
+}
+
+// Parameters of the certificates generated by GenerateDevCerts.
+const (
+	devCertKeySize  = 2048
+	devCALifetime   = 366 * 24 * time.Hour
+	devCertLifetime = 366 * 24 * time.Hour
+)
+
+// GenerateDevCerts generates a CA, a node certificate and a client
+// certificate for the root user, so that a secure development cluster can be
+// started without generating certificates manually. The certificates are
+// written to the certificates directory, or to a new temporary directory if
+// none is configured, in which case SSLCertsDir is pointed at it. The CA key
+// is written alongside the certificates, which makes them unsuitable for
+// anything but development clusters.
+//
+// GenerateDevCerts refuses to overwrite any existing certificate or key.
+func (cfg *BaseConfig) GenerateDevCerts() error {
+	if cfg.Insecure {
+		return errors.New("cannot generate certificates for an insecure server")
+	}
+	certsDir := cfg.SSLCertsDir
+	if certsDir == "" {
+		dir, err := os.MkdirTemp("", "cockroach-dev-certs")
+		if err != nil {
+			return errors.Wrap(err, "creating certificates directory")
+		}
+		certsDir = dir
+	}
+
+	root := username.RootUserName()
+	caKeyPath := filepath.Join(certsDir, certnames.CAKeyFilename())
+	var existing []string
+	for _, name := range []string{
+		certnames.CACertFilename(), certnames.CAKeyFilename(),
+		certnames.NodeCertFilename(), certnames.NodeKeyFilename(),
+		certnames.ClientCertFilename(root), certnames.ClientKeyFilename(root),
+	} {
+		path := filepath.Join(certsDir, name)
+		if _, err := os.Stat(path); err == nil {
+			existing = append(existing, path)
+		} else if !oserror.IsNotExist(err) {
+			return err
+		}
+	}
+	if len(existing) > 0 {
+		err := errors.Newf("cannot generate certificates: %s already contains certificates", certsDir)
+		for _, path := range existing {
+			err = errors.WithDetailf(err, "Existing file: %s", path)
+		}
+		return err
+	}
+
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+	for _, addr := range []string{cfg.AdvertiseAddr, cfg.SQLAdvertiseAddr, cfg.HTTPAdvertiseAddr} {
+		if host, _, err := net.SplitHostPort(addr); err == nil && host != "" &&
+			!slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+	if err := security.CreateCAPair(certsDir, caKeyPath, devCertKeySize, devCALifetime,
+		false /* allowKeyReuse */, false /* overwrite */); err != nil {
+		return errors.Wrap(err, "generating CA certificate")
+	}
+	if err := security.CreateNodePair(certsDir, caKeyPath, devCertKeySize, devCertLifetime,
+		false /* overwrite */, hosts); err != nil {
+		return errors.Wrap(err, "generating node certificate")
+	}
+	if err := security.CreateClientPair(certsDir, caKeyPath, devCertKeySize, devCertLifetime,
+		false /* overwrite */, root, []roachpb.TenantID{roachpb.SystemTenantID},
+		false /* wantPKCS8Key */); err != nil {
+		return errors.Wrap(err, "generating root client certificate")
+	}
+	cfg.SSLCertsDir = certsDir
+	return nil
+}
+
+// validateDrainAllowedUsers checks that DrainAllowedUsers only contains valid
+// user names.
+func (cfg *BaseConfig) validateDrainAllowedUsers() error {
+	for _, u := range cfg.DrainAllowedUsers {
+		if _, err := username.MakeSQLUsernameFromUserInput(u, username.PurposeCreation); err != nil {
+			return errors.Wrapf(err, "invalid drain allowed user %q", u)
+		}
+	}
+	return nil
+}
+
+// auditLogFileGroup is the name of the logging file group that the SQL audit
+// log is written to.
+const auditLogFileGroup = "sql-audit"
+
+// validateAuditLog checks that the audit log rotation options are
+// non-negative, zero meaning unset. The audit log directory is checked by
+// ConfigureAuditLog.
+func (cfg *BaseConfig) validateAuditLog() error {
+	if cfg.AuditLogMaxSize < 0 {
+		return errors.Errorf("audit log max size must be non-negative, got %d", cfg.AuditLogMaxSize)
+	}
+	if cfg.AuditLogMaxFiles < 0 {
+		return errors.Errorf("audit log max files must be non-negative, got %d", cfg.AuditLogMaxFiles)
+	}
+	if cfg.AuditLogMaxFiles > 0 && cfg.AuditLogMaxSize == 0 {
+		return errors.New("audit log max files requires audit log max size to be set")
+	}
+	return nil
+}
+
+// ConfigureAuditLog applies the audit log options to the SQL audit file group
+// of the given logging configuration. Configurations without such a group are
+// left untouched. The AuditLogDir, if set, is created if needed and checked
+// to be writable.
+func (cfg *BaseConfig) ConfigureAuditLog(c *logconfig.Config) error {
+	fc, ok := c.Sinks.FileGroups[auditLogFileGroup]
+	if !ok {
+		return nil
+	}
+	if cfg.AuditLogDir != "" {
+		if err := checkAuditLogDir(cfg.AuditLogDir); err != nil {
+			return err
+		}
+		dir := cfg.AuditLogDir
+		fc.Dir = &dir
+	}
+	if cfg.AuditLogMaxSize > 0 {
+		fileSize := logconfig.ByteSize(cfg.AuditLogMaxSize)
+		fc.MaxFileSize = &fileSize
+		if cfg.AuditLogMaxFiles > 0 {
+			groupSize := fileSize * logconfig.ByteSize(cfg.AuditLogMaxFiles)
+			fc.MaxGroupSize = &groupSize
+		}
+	}
 	return nil
 }
 
+// checkAuditLogDir creates the given audit log directory if needed and checks
+// that it can be written to.
+func checkAuditLogDir(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrapf(err, "audit log directory %s", dir)
+	}
+	f, err := os.CreateTemp(dir, ".writable-check-*")
+	if err != nil {
+		return errors.Wrapf(err, "audit log directory %s is not writable", dir)
+	}
+	_ = f.Close()
+	return os.Remove(f.Name())
+}
+
+// validateInsecureExposure checks that an insecure server only listens on and
+// advertises loopback hosts, since anyone able to reach an insecure server has
+// full access to the cluster. AcceptInsecureExposure lifts the check.
+//
+// Addresses which leave the host unspecified, like the default ":26257", are
+// exempt even though they bind all interfaces just like "0.0.0.0". They are the
+// defaults, and rejecting them would break every `start --insecure` without
+// explicit addresses, including local development clusters. The check instead
+// targets hosts that were explicitly configured to be reachable from the
+// network.
+func (cfg *BaseConfig) validateInsecureExposure() error {
+	if !cfg.Insecure || cfg.AcceptInsecureExposure {
+		return nil
+	}
+	for _, a := range []struct {
+		name, addr string
+	}{
+		{"listen", cfg.Addr},
+		{"advertise", cfg.AdvertiseAddr},
+		{"SQL listen", cfg.SQLAddr},
+		{"SQL advertise", cfg.SQLAdvertiseAddr},
+		{"HTTP listen", cfg.HTTPAddr},
+		{"HTTP advertise", cfg.HTTPAdvertiseAddr},
+	} {
+		if addrHost(a.addr) == "" || isLoopbackAddr(a.addr) {
+			continue
+		}
+		return errors.WithHint(
+			errors.Newf("insecure mode is not allowed with the non-loopback %s address %q", a.name, a.addr),
+			"Use loopback addresses, start the node with certificates, "+
+				"or pass --accept-insecure-exposure to expose the insecure node to the network anyway.")
+	}
+	return nil
+}
+
+// addrHost returns the host part of the given address, which may not have a
+// port.
+func addrHost(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		// No port.
+		return addr
+	}
+	return host
+}
+
+// isLoopbackAddr returns whether the host of the given address is a loopback
+// address. An empty host, which listens on all interfaces, is not.
+func isLoopbackAddr(addr string) bool {
+	host := addrHost(addr)
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// validateMaxConnsPerIP checks that the per-IP SQL connection limit is not
+// negative.
+func (cfg *BaseConfig) validateMaxConnsPerIP() error {
+	if cfg.MaxConnsPerIP < 0 {
+		return errors.Errorf("maximum connections per IP must be non-negative, got %d",
+			cfg.MaxConnsPerIP)
+	}
+	return nil
+}
+
+// warnf logs an advisory configuration warning and records it so that it is
+// also reported by Warnings.
+func (cfg *BaseConfig) warnf(ctx context.Context, format string, args ...interface{}) {
+	log.Ops.Warningf(ctx, format, args...)
+	cfg.warnings = append(cfg.warnings, fmt.Sprintf(format, args...))
+}
+
+// Warnings returns the advisory configuration warnings logged so far while
+// initializing and validating the configuration, in the order they were
+// logged, so that they can be reported together at startup.
+func (cfg *BaseConfig) Warnings() []string {
+	return cfg.warnings
+}
+
+// linearizableMaxOffsetWarnThreshold is the MaxOffset above which
+// warnLinearizableMaxOffset reports the commit latency implied by
+// Linearizable.
+const linearizableMaxOffsetWarnThreshold = 250 * time.Millisecond
+
+// warnLinearizableMaxOffset logs a warning when Linearizable is enabled with
+// a MaxOffset above linearizableMaxOffsetWarnThreshold: every commit then
+// waits out the full offset before being acknowledged. The warning is purely
+// advisory. It returns whether the warning was logged.
+func (cfg *Config) warnLinearizableMaxOffset(ctx context.Context) bool {
+	mo := time.Duration(cfg.MaxOffset)
+	if !cfg.Linearizable || mo <= linearizableMaxOffsetWarnThreshold {
+		return false
+	}
+	cfg.warnf(ctx, "linearizable mode is enabled with a max clock offset of %s; "+
+		"every commit will wait up to %s before being acknowledged", mo, mo)
+	return true
+}
+
+// validateMaxNewConnsPerSecond checks that the SQL connection rate limit is
+// not negative.
+func (cfg *BaseConfig) validateMaxNewConnsPerSecond() error {
+	if cfg.MaxNewConnsPerSecond < 0 {
+		return errors.Errorf("maximum new connections per second must be non-negative, got %d",
+			cfg.MaxNewConnsPerSecond)
+	}
+	return nil
+}
+
+// validateMaxOffset checks that MaxOffset is neither negative nor above
+// maximumMaxClockOffset, the bound also enforced by the --max-offset flag. A
+// larger offset would let clocks drift further apart than the uncertainty
+// intervals of transactions account for, voiding linearizability.
+func (cfg *BaseConfig) validateMaxOffset() error {
+	if mo := time.Duration(cfg.MaxOffset); mo < 0 || mo > maximumMaxClockOffset {
+		return errors.Errorf("max clock offset must be between 0 and %s, got %s",
+			maximumMaxClockOffset, mo)
+	}
+	return nil
+}
+
+// validatePGSSLMode checks that PGSSLMode, if set, is one of the sslmode
+// values understood by libpq.
+func (cfg *BaseConfig) validatePGSSLMode() error {
+	switch pgurl.TLSMode(cfg.PGSSLMode) {
+	case pgurl.TLSUnspecified, pgurl.TLSVerifyFull, pgurl.TLSVerifyCA,
+		pgurl.TLSRequire, pgurl.TLSPrefer, pgurl.TLSAllow, "disable":
+		return nil
+	}
+	return errors.Errorf("invalid PG sslmode %q; valid modes are "+
+		"disable, allow, prefer, require, verify-ca and verify-full", cfg.PGSSLMode)
+}
+
+// validateDefaultGCTTL checks that DefaultGCTTL is either zero or at least
+// one second, the granularity of GC TTLs.
+func (cfg *KVConfig) validateDefaultGCTTL() error {
+	if d := cfg.DefaultGCTTL; d < 0 || (d > 0 && d < time.Second) {
+		return errors.Errorf("default GC TTL must be zero or at least 1s, got %s", d)
+	}
+	return nil
+}
+
+// validateDefaultHashShardedBuckets checks that DefaultHashShardedBuckets is
+// either zero or a valid hash sharded index bucket count.
+func (cfg *SQLConfig) validateDefaultHashShardedBuckets() error {
+	if n := cfg.DefaultHashShardedBuckets; n != 0 && (n < 2 || n > tabledesc.MaxBucketAllowed) {
+		return errors.Errorf("default hash sharded bucket count must be 0 or in range [2, %d], got %d",
+			tabledesc.MaxBucketAllowed, n)
+	}
+	return nil
+}
+
+// validateMaxSessionResultBytes checks that MaxSessionResultBytes is not
+// negative and fits within the SQL memory pool.
+func (cfg *SQLConfig) validateMaxSessionResultBytes() error {
+	if n := cfg.MaxSessionResultBytes; n < 0 {
+		return errors.Errorf("max session result bytes must be non-negative, got %d", n)
+	} else if n > 0 && n >= cfg.MemoryPoolSize {
+		return errors.Errorf("max session result bytes (%s) must be less than the SQL memory pool size (%s)",
+			humanizeutil.IBytes(n), humanizeutil.IBytes(cfg.MemoryPoolSize))
+	}
+	return nil
+}
+
+// maxDescriptorLeaseDuration bounds DescriptorLeaseDuration. Schema changes
+// wait for the leases on the previous descriptor versions to expire, so very
+// long leases can stall them.
+const maxDescriptorLeaseDuration = time.Hour
+
+// validateDescriptorLeaseDuration checks that DescriptorLeaseDuration is
+// either zero or a positive duration no longer than
+// maxDescriptorLeaseDuration.
+func (cfg *SQLConfig) validateDescriptorLeaseDuration() error {
+	if d := cfg.DescriptorLeaseDuration; d < 0 || d > maxDescriptorLeaseDuration {
+		return errors.Errorf("descriptor lease duration must be in range [0, %s], got %s",
+			maxDescriptorLeaseDuration, d)
+	}
+	return nil
+}
+
+// validateDefaultDistSQLMode checks that DefaultDistSQLMode, if set, is one of
+// the modes which can be chosen as a cluster default.
+func (cfg *SQLConfig) validateDefaultDistSQLMode() error {
+	switch cfg.DefaultDistSQLMode {
+	case "", "off", "auto", "on":
+		return nil
+	default:
+		return errors.Errorf("default distsql mode must be one of off, auto or on, got %q",
+			cfg.DefaultDistSQLMode)
+	}
+}
+
+// validateSlowQueryLogThreshold checks that SlowQueryLogThreshold is not
+// negative.
+func (cfg *SQLConfig) validateSlowQueryLogThreshold() error {
+	if cfg.SlowQueryLogThreshold < 0 {
+		return errors.Errorf("slow query log threshold must be non-negative, got %s",
+			cfg.SlowQueryLogThreshold)
+	}
+	return nil
+}
+
+// validateAutoStatsMinStaleRows checks that AutoStatsMinStaleRows is not
+// negative.
+func (cfg *SQLConfig) validateAutoStatsMinStaleRows() error {
+	if cfg.AutoStatsMinStaleRows < 0 {
+		return errors.Errorf("auto stats min stale rows must be non-negative, got %d",
+			cfg.AutoStatsMinStaleRows)
+	}
+	return nil
+}
+
+// validateDefaultTimeZone checks that DefaultTimeZone, if set, names a known
+// time zone.
+func (cfg *SQLConfig) validateDefaultTimeZone() error {
+	if cfg.DefaultTimeZone == "" {
+		return nil
+	}
+	if _, err := timeutil.LoadLocation(cfg.DefaultTimeZone); err != nil {
+		return errors.Wrapf(err, "invalid default time zone %q", cfg.DefaultTimeZone)
+	}
+	return nil
+}
+
+// validateBootstrapBackupSchedule checks that BootstrapBackupSchedule and
+// BootstrapBackupURI are either both empty or, respectively, a valid cron
+// expression and a URI with a scheme. The URI is not included in the errors,
+// as it may embed credentials. As backups are implemented in CCL code, the
+// schedule is also rejected by OSS binaries, where creating it would fail the
+// initial SQL of the cluster.
+func (cfg *SQLConfig) validateBootstrapBackupSchedule() error {
+	if cfg.BootstrapBackupSchedule == "" && cfg.BootstrapBackupURI == "" {
+		return nil
+	}
+	if cfg.BootstrapBackupSchedule == "" || cfg.BootstrapBackupURI == "" {
+		return errors.New("the bootstrap backup schedule and URI must be set together")
+	}
+	if _, err := cron.ParseStandard(cfg.BootstrapBackupSchedule); err != nil {
+		return errors.Wrapf(err, "invalid bootstrap backup schedule %q", cfg.BootstrapBackupSchedule)
+	}
+	if u, err := url.Parse(cfg.BootstrapBackupURI); err != nil || u.Scheme == "" {
+		return errors.WithHint(errors.New("invalid bootstrap backup URI"),
+			"The URI must name an external storage location, e.g. s3://bucket/path or nodelocal://1/path.")
+	}
+	if build.Distribution == "OSS" {
+		return errors.New("a bootstrap backup schedule requires a CCL binary")
+	}
+	return nil
+}
+
+// Validate checks the configuration of a KV node against all the invariants
+// which InitNode enforces, once the parts of the configuration coming from
+// the environment have been read, and combines the violations, if any, into
+// a single error listing each of them on a line of its own. The checks are
+// the validate methods returned by validations, which can be tested
+// independently.
+//
+// Validate does not access the filesystem.
+func (cfg *Config) Validate() error {
+	var errs []error
+	for _, validate := range cfg.validations() {
+		if err := validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
+
+// validations returns the checks performed by Validate, in order.
+func (cfg *Config) validations() []func() error {
+	return []func() error{
+		cfg.validateInsecureExposure,
+		cfg.ValidateListenerPorts,
+		cfg.validateMaxConnsPerIP,
+		cfg.validateMaxNewConnsPerSecond,
+		cfg.validatePGSSLMode,
+		cfg.validateMaxOffset,
+		cfg.validateAuditLog,
+		cfg.validateDrainAllowedUsers,
+		cfg.validateHTTPRequestTimeout,
+		cfg.validateRPCServerConcurrency,
+		cfg.validateMinTLSVersion,
+		cfg.validateQueueConcurrency,
+		cfg.validateRaftTimeouts,
+		cfg.validateRaftLogTruncationThreshold,
+		cfg.validateMinStoresToStart,
+		func() error { return cfg.validateAddressableMemory(maxAddressableMemory) },
+		cfg.validateDefaultMaxIntentsBytes,
+		cfg.DefaultAdmissionConfig.validate,
+		cfg.validateJoinRebalanceRate,
+		cfg.validateExpectedClusterSize,
+		cfg.validateDefaultGCTTL,
+		cfg.validateDefaultHashShardedBuckets,
+		cfg.validateMaxSessionResultBytes,
+		cfg.validateDescriptorLeaseDuration,
+		cfg.validateDefaultDistSQLMode,
+		cfg.validateSlowQueryLogThreshold,
+		cfg.validateAutoStatsMinStaleRows,
+		cfg.validateDefaultTimeZone,
+		cfg.validateBootstrapBackupSchedule,
+	}
+}
+
 // InitNode finalizes the configuration of a KV node.
 // It parses node attributes and bootstrap addresses and
 // initializes additional configuration flags from the environment.
 func (cfg *Config) InitNode(ctx context.Context) error {
 	cfg.readEnvironmentVariables()
 
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	cfg.warnLinearizableMaxOffset(ctx)
+
 	// Initialize attributes.
-	cfg.NodeAttributes = parseAttributes(cfg.Attrs)
+	attrs, err := parseAttributes(cfg.Attrs)
+	if err != nil {
+		return err
+	}
+	cfg.NodeAttributes = attrs
 
 	// Get the gossip bootstrap addresses.
 	addresses, err := cfg.parseGossipBootstrapAddresses(ctx)
@@ -918,6 +2495,9 @@ func (cfg *Config) InitNode(ctx context.Context) error {
 	if len(addresses) > 0 {
 		cfg.GossipBootstrapAddresses = addresses
 	}
+	if cfg.PrewarmDNS {
+		prewarmDNS(ctx, cfg.GossipBootstrapAddresses)
+	}
 
 	cfg.BaseConfig.idProvider.SetTenantID(roachpb.SystemTenantID)
 	cfg.BaseConfig.idProvider.SetTenantName(catconstants.SystemTenantName)
@@ -925,6 +2505,43 @@ func (cfg *Config) InitNode(ctx context.Context) error {
 	return nil
 }
 
+// dnsPrewarmTimeout bounds each of the lookups issued by prewarmDNS.
+const dnsPrewarmTimeout = 10 * time.Second
+
+// lookupHost is used by prewarmDNS to resolve host names. It is overridden in
+// tests.
+var lookupHost = net.DefaultResolver.LookupHost
+
+// prewarmDNS resolves the host names among addrs in the background. IP
+// literals are skipped. Failures are only logged: the gossip bootstrap
+// resolves the names again when it connects.
+func prewarmDNS(ctx context.Context, addrs []util.UnresolvedAddr) {
+	var hosts []string
+	for _, a := range addrs {
+		host, _, err := net.SplitHostPort(a.AddressField)
+		if err != nil || host == "" || net.ParseIP(host) != nil {
+			continue
+		}
+		hosts = append(hosts, host)
+	}
+	if len(hosts) == 0 {
+		return
+	}
+	// The lookups outlive the caller's context.
+	ctx = logtags.WithTags(context.Background(), logtags.FromContext(ctx))
+	go func() {
+		for _, host := range hosts {
+			func() {
+				ctx, cancel := context.WithTimeout(ctx, dnsPrewarmTimeout)
+				defer cancel()
+				if _, err := lookupHost(ctx, host); err != nil {
+					log.Ops.Warningf(ctx, "unable to prewarm DNS resolution of %q: %v", host, err)
+				}
+			}()
+		}
+	}()
+}
+
 // FilterGossipBootstrapAddresses removes any gossip bootstrap addresses which
 // match either this node's listen address or its advertised host address.
 func (cfg *Config) FilterGossipBootstrapAddresses(ctx context.Context) []util.UnresolvedAddr {
@@ -972,6 +2589,22 @@ func (cfg *Config) readEnvironmentVariables() {
 	cfg.SnapshotApplyLimit = envutil.EnvOrDefaultInt64("COCKROACH_CONCURRENT_SNAPSHOT_APPLY_LIMIT", cfg.SnapshotApplyLimit)
 }
 
+// EnvBlock renders the configuration values which readEnvironmentVariables
+// reads from the environment, i.e. the variables it lists, as the
+// environment, e.g. of a Kubernetes StatefulSet, which reproduces them. Other
+// environment variables consulted by the server, e.g. when the Config is
+// made, are not included. None of these values are sensitive.
+func (cfg *Config) EnvBlock() map[string]string {
+	return map[string]string{
+		"COCKROACH_EXPERIMENTAL_LINEARIZABLE":       strconv.FormatBool(cfg.Linearizable),
+		"COCKROACH_SCAN_INTERVAL":                   cfg.ScanInterval.String(),
+		"COCKROACH_SCAN_MIN_IDLE_TIME":              cfg.ScanMinIdleTime.String(),
+		"COCKROACH_SCAN_MAX_IDLE_TIME":              cfg.ScanMaxIdleTime.String(),
+		"COCKROACH_CONCURRENT_SNAPSHOT_SEND_LIMIT":  strconv.FormatInt(cfg.SnapshotSendLimit, 10),
+		"COCKROACH_CONCURRENT_SNAPSHOT_APPLY_LIMIT": strconv.FormatInt(cfg.SnapshotApplyLimit, 10),
+	}
+}
+
 // parseGossipBootstrapAddresses parses list of gossip bootstrap addresses.
 func (cfg *Config) parseGossipBootstrapAddresses(
 	ctx context.Context,
@@ -1014,17 +2647,43 @@ func (cfg *Config) parseGossipBootstrapAddresses(
 	return bootstrapAddresses, nil
 }
 
-// parseAttributes parses a colon-separated list of strings,
-// filtering empty strings (i.e. "::" will yield no attributes.
-// Returns the list of strings as Attributes.
-func parseAttributes(attrsStr string) roachpb.Attributes {
+// attributeReservedChars are the characters which an attribute may not
+// contain, as they separate the options of store specs. '=' is allowed, since
+// only the first '=' of a store spec option separates its value.
+const attributeReservedChars = ","
+
+// parseAttributes parses a colon-separated list of strings, filtering empty
+// strings (i.e. "::" will yield no attributes) and repeated ones, which keep
+// their first position. Attributes containing any of attributeReservedChars
+// are rejected. Returns the list of strings as Attributes.
+func parseAttributes(attrsStr string) (roachpb.Attributes, error) {
+	var attrs []string
+	seen := make(map[string]struct{})
+	for _, attr := range splitAttributes(attrsStr) {
+		if strings.ContainsAny(attr, attributeReservedChars) {
+			return roachpb.Attributes{}, errors.Errorf("attribute %q must not contain any of %q",
+				attr, attributeReservedChars)
+		}
+		if _, ok := seen[attr]; ok {
+			continue
+		}
+		seen[attr] = struct{}{}
+		attrs = append(attrs, attr)
+	}
+	return roachpb.Attributes{Attrs: attrs}, nil
+}
+
+// splitAttributes splits a colon-separated list of strings, filtering empty
+// strings, without validating them. It is meant for rendering Attrs, which
+// parseAttributes validates when the node is initialized.
+func splitAttributes(attrsStr string) []string {
 	var filtered []string
 	for _, attr := range strings.Split(attrsStr, ":") {
 		if len(attr) != 0 {
 			filtered = append(filtered, attr)
 		}
 	}
-	return roachpb.Attributes{Attrs: filtered}
+	return filtered
 }
 
 // idProvider connects the server ID containers in this
@@ -0,0 +1,153 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/cockroachdb/redact"
+)
+
+// FieldDiff describes a configuration field whose value differs between two
+// configurations.
+type FieldDiff struct {
+	// Field is the dotted path of the field within Config. Fields of embedded
+	// structs are named as if they were declared in the embedding struct.
+	Field string
+	// Old and New are the formatted values of the field in the receiver and
+	// the argument of Diff, respectively. They are redacted for sensitive
+	// fields.
+	Old, New string
+}
+
+// sensitiveConfigFields are the configuration fields whose values may carry
+// credentials, and which are redacted in configuration diffs.
+var sensitiveConfigFields = map[string]struct{}{
+	// SharedStorage and BootstrapBackupURI are external storage URIs, which
+	// may embed access keys.
+	"SharedStorage":      {},
+	"BootstrapBackupURI": {},
+	"SSLCAKey":           {},
+}
+
+// Diff returns the configuration fields whose values differ between cfg and
+// other, in declaration order.
+//
+// Only fields holding plain data (scalars, and slices, maps, pointers and
+// structs thereof) are compared. Runtime dependencies such as the settings,
+// tracer, testing knobs and callbacks are not configuration in this sense and
+// are ignored.
+func (cfg *Config) Diff(other *Config) []FieldDiff {
+	d := configDiffer{plain: make(map[reflect.Type]bool)}
+	d.diff("", reflect.ValueOf(cfg).Elem(), reflect.ValueOf(other).Elem())
+	return d.diffs
+}
+
+type configDiffer struct {
+	diffs []FieldDiff
+	// plain memoizes isPlainData.
+	plain map[reflect.Type]bool
+}
+
+func (d *configDiffer) diff(path string, a, b reflect.Value) {
+	t := a.Type()
+	if d.isPlainData(t) {
+		if reflect.DeepEqual(a.Interface(), b.Interface()) {
+			return
+		}
+		fd := FieldDiff{Field: path}
+		if isSensitiveConfigPath(path) {
+			fd.Old, fd.New = string(redact.RedactedMarker()), string(redact.RedactedMarker())
+		} else {
+			fd.Old, fd.New = formatConfigValue(a), formatConfigValue(b)
+		}
+		d.diffs = append(d.diffs, fd)
+		return
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			fieldPath := f.Name
+			if f.Anonymous {
+				fieldPath = path
+			} else if path != "" {
+				fieldPath = path + "." + f.Name
+			}
+			fa, fb := a.Field(i), b.Field(i)
+			if f.Anonymous && f.Type.Kind() == reflect.Pointer {
+				// Embedded configuration structs, like *base.Config, are
+				// compared as if their fields were declared inline.
+				if fa.IsNil() || fb.IsNil() {
+					continue
+				}
+				fa, fb = fa.Elem(), fb.Elem()
+			}
+			d.diff(fieldPath, fa, fb)
+		}
+	default:
+		// Not configuration data; see Diff.
+	}
+}
+
+// isPlainData returns whether values of type t consist only of data which can
+// be meaningfully compared with reflect.DeepEqual across configurations.
+func (d *configDiffer) isPlainData(t reflect.Type) bool {
+	if plain, ok := d.plain[t]; ok {
+		return plain
+	}
+	// Guard against recursive types.
+	d.plain[t] = false
+	var plain bool
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.String:
+		plain = true
+	case reflect.Array, reflect.Slice, reflect.Pointer:
+		plain = d.isPlainData(t.Elem())
+	case reflect.Map:
+		plain = d.isPlainData(t.Key()) && d.isPlainData(t.Elem())
+	case reflect.Struct:
+		plain = true
+		for i := 0; i < t.NumField() && plain; i++ {
+			plain = d.isPlainData(t.Field(i).Type)
+		}
+	}
+	d.plain[t] = plain
+	return plain
+}
+
+// isSensitiveConfigPath returns whether the field at the given path is one of
+// the sensitiveConfigFields.
+func isSensitiveConfigPath(path string) bool {
+	if i := strings.LastIndexByte(path, '.'); i >= 0 {
+		path = path[i+1:]
+	}
+	_, ok := sensitiveConfigFields[path]
+	return ok
+}
+
+func formatConfigValue(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "<nil>"
+		}
+		v = v.Elem()
+	}
+	return fmt.Sprintf("%v", v.Interface())
+}
@@ -0,0 +1,48 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/redact"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigDiff(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	oldCfg := MakeConfig(ctx, cluster.MakeTestingClusterSettings())
+	newCfg := MakeConfig(ctx, cluster.MakeTestingClusterSettings())
+	require.Empty(t, oldCfg.Diff(&newCfg))
+
+	newCfg.CacheSize = oldCfg.CacheSize * 2
+	newCfg.Stores = base.StoreSpecList{Specs: []base.StoreSpec{{Path: "/mnt/data1"}, {Path: "/mnt/data2"}}}
+	require.Equal(t, []FieldDiff{
+		{Field: "CacheSize", Old: "134217728", New: "268435456"},
+		{Field: "Stores", Old: oldCfg.Stores.String(), New: newCfg.Stores.String()},
+	}, oldCfg.Diff(&newCfg))
+
+	// Sensitive fields are redacted.
+	newCfg = oldCfg
+	newCfg.SharedStorage = "s3://bucket?AWS_SECRET_ACCESS_KEY=secret"
+	marker := string(redact.RedactedMarker())
+	require.Equal(t, []FieldDiff{
+		{Field: "SharedStorage", Old: marker, New: marker},
+	}, oldCfg.Diff(&newCfg))
+}
@@ -12,23 +12,44 @@ package server
 
 import (
 	"context"
+	"fmt"
+	"math"
 	"net"
+	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
+	"regexp"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
 	"github.com/cockroachdb/cockroach/pkg/base/serverident"
+	"github.com/cockroachdb/cockroach/pkg/build"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/security/certnames"
+	"github.com/cockroachdb/cockroach/pkg/security/clientsecopts"
+	"github.com/cockroachdb/cockroach/pkg/security/securityassets"
+	"github.com/cockroachdb/cockroach/pkg/security/securitytest"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
+	"github.com/cockroachdb/cockroach/pkg/server/pgurl"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/envutil"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/logconfig"
 	"github.com/cockroachdb/cockroach/pkg/util/netutil"
 	"github.com/cockroachdb/cockroach/pkg/util/netutil/addr"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/pebble/vfs"
+	"github.com/cockroachdb/pebble/vfs/errorfs"
+	"github.com/gogo/protobuf/proto"
 	"github.com/kr/pretty"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -54,6 +75,52 @@ func TestParseInitNodeAttributes(t *testing.T) {
 	}
 }
 
+// TestParseInitNodeAttributesInvalid verifies that InitNode fails on an
+// attribute containing a reserved character.
+func TestParseInitNodeAttributesInvalid(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	cfg.Attrs = "attr1:attr2,attr3"
+	cfg.Stores = base.StoreSpecList{Specs: []base.StoreSpec{{InMemory: true, Size: base.SizeSpec{InBytes: base.MinimumStoreSize * 100}}}}
+	engines, err := cfg.CreateEngines(context.Background())
+	require.NoError(t, err)
+	defer engines.Close()
+	require.EqualError(t, cfg.InitNode(context.Background()),
+		`attribute "attr2,attr3" must not contain any of ","`)
+}
+
+func TestParseAttributes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testCases := []struct {
+		attrs       string
+		expected    []string
+		expectedErr string
+	}{
+		{"", nil, ""},
+		{"::", nil, ""},
+		{"ssd:fast", []string{"ssd", "fast"}, ""},
+		// Repeated attributes keep their first position.
+		{"fast:ssd:fast::ssd:gpu", []string{"fast", "ssd", "gpu"}, ""},
+		{"attr1=val1::attr2=val2", []string{"attr1=val1", "attr2=val2"}, ""},
+		{"ssd,fast", nil, `attribute "ssd,fast" must not contain any of ","`},
+		{"ssd:rack=1,fast", nil, `attribute "rack=1,fast" must not contain any of ","`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.attrs, func(t *testing.T) {
+			attrs, err := parseAttributes(tc.attrs)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, attrs.Attrs)
+		})
+	}
+}
+
 // TestParseJoinUsingAddrs verifies that JoinList is parsed
 // correctly.
 func TestParseJoinUsingAddrs(t *testing.T) {
@@ -182,6 +249,92 @@ func TestReadEnvironmentVariables(t *testing.T) {
 	}
 }
 
+func TestValidateInsecureExposure(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	setAddrs := func(addr string) {
+		cfg.Addr, cfg.AdvertiseAddr = addr, addr
+		cfg.SQLAddr, cfg.SQLAdvertiseAddr = addr, addr
+		cfg.HTTPAddr, cfg.HTTPAdvertiseAddr = addr, addr
+	}
+
+	// Secure nodes may listen anywhere.
+	cfg.Insecure = false
+	setAddrs("0.0.0.0:26257")
+	require.NoError(t, cfg.validateInsecureExposure())
+
+	cfg.Insecure = true
+	require.ErrorContains(t, cfg.validateInsecureExposure(),
+		`insecure mode is not allowed with the non-loopback listen address "0.0.0.0:26257"`)
+	setAddrs("cockroach:26257")
+	require.Error(t, cfg.validateInsecureExposure())
+	// Addresses without a host bind all interfaces like "0.0.0.0", but they are
+	// the defaults and are deliberately exempt.
+	for _, addr := range []string{
+		"", ":26257", "127.0.0.1:26257", "[::1]:26257", "localhost:26257", "127.0.0.1",
+	} {
+		setAddrs(addr)
+		require.NoError(t, cfg.validateInsecureExposure(), addr)
+	}
+	defaultCfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	defaultCfg.Insecure = true
+	require.NoError(t, defaultCfg.validateInsecureExposure())
+	cfg.SQLAdvertiseAddr = "10.0.0.1:26257"
+	require.ErrorContains(t, cfg.validateInsecureExposure(), "SQL advertise address")
+	setAddrs("127.0.0.1:26257")
+	cfg.HTTPAddr = "10.0.0.1:8080"
+	require.ErrorContains(t, cfg.validateInsecureExposure(), "HTTP listen address")
+
+	// The exposure can be accepted explicitly.
+	cfg.AcceptInsecureExposure = true
+	setAddrs("0.0.0.0:26257")
+	require.NoError(t, cfg.validateInsecureExposure())
+}
+
+func TestEnvBlock(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	st := cluster.MakeTestingClusterSettings()
+	cfg := MakeConfig(context.Background(), st)
+	cfg.Linearizable = true
+	cfg.ScanInterval = 48 * time.Hour
+	cfg.ScanMinIdleTime = 10 * time.Millisecond
+	cfg.ScanMaxIdleTime = 2*time.Second + 500*time.Millisecond
+	cfg.SnapshotSendLimit = 4
+	cfg.SnapshotApplyLimit = 3
+
+	// The environment reproduces the configuration.
+	env := cfg.EnvBlock()
+	for name, value := range env {
+		defer envutil.TestSetEnv(t, name, value)()
+	}
+	restored := MakeConfig(context.Background(), st)
+	envutil.ClearEnvCache()
+	restored.readEnvironmentVariables()
+	require.Empty(t, cfg.Diff(&restored))
+	require.Equal(t, "48h0m0s", env["COCKROACH_SCAN_INTERVAL"])
+
+	// The block covers every variable read by readEnvironmentVariables, as
+	// recorded in the environment report since the cache was cleared.
+	var read []string
+	for _, line := range strings.Split(envutil.GetEnvReport(), "\n") {
+		if fields := strings.Fields(line); len(fields) > 1 {
+			if fields[0] == "#" {
+				read = append(read, fields[1])
+			} else {
+				read = append(read, fields[0])
+			}
+		}
+	}
+	require.NotEmpty(t, read)
+	for _, name := range read {
+		require.Contains(t, env, name)
+	}
+}
+
 func TestFilterGossipBootstrapAddresses(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -204,6 +357,31 @@ func TestFilterGossipBootstrapAddresses(t *testing.T) {
 	}
 }
 
+func TestPrewarmDNS(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	lookups := make(chan string, 4)
+	defer func(prev func(context.Context, string) ([]string, error)) { lookupHost = prev }(lookupHost)
+	lookupHost = func(_ context.Context, host string) ([]string, error) {
+		lookups <- host
+		return nil, errors.New("no such host")
+	}
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	cfg.PrewarmDNS = true
+	cfg.JoinList = base.JoinListType{"node1.example.com", "127.0.0.1", "[::1]:26258", "node2.example.com:26259"}
+	require.NoError(t, cfg.InitNode(context.Background()))
+
+	require.Equal(t, "node1.example.com", <-lookups)
+	require.Equal(t, "node2.example.com", <-lookups)
+	select {
+	case host := <-lookups:
+		t.Fatalf("unexpected lookup of %q", host)
+	default:
+	}
+}
+
 func TestParseBootstrapResolvers(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -334,3 +512,1413 @@ func TestIdProviderServerIdentityString(t *testing.T) {
 		})
 	}
 }
+
+func TestDivideProvisionedBandwidth(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	disk := base.StoreSpec{Path: "/mnt/data"}
+	mem := base.StoreSpec{InMemory: true}
+	explicit := func(rate int64) base.StoreSpec {
+		return base.StoreSpec{
+			Path:                "/mnt/data",
+			ProvisionedRateSpec: base.ProvisionedRateSpec{ProvisionedBandwidth: rate},
+		}
+	}
+
+	for _, tc := range []struct {
+		name       string
+		specs      []base.StoreSpec
+		capacities []int64
+		exp        []int64
+		expErr     string
+	}{
+		{
+			name:       "by capacity",
+			specs:      []base.StoreSpec{disk, disk},
+			capacities: []int64{100 << 30, 200 << 30},
+			exp:        []int64{100 << 20, 200 << 20},
+		},
+		{
+			name:       "explicit rate subtracted",
+			specs:      []base.StoreSpec{explicit(100 << 20), disk, disk},
+			capacities: []int64{100 << 30, 100 << 30, 300 << 30},
+			exp:        []int64{0, 50 << 20, 150 << 20},
+		},
+		{
+			name:       "unknown capacities exclude in-memory stores",
+			specs:      []base.StoreSpec{mem, disk, disk},
+			capacities: []int64{0, 0, 0},
+			exp:        []int64{0, 150 << 20, 150 << 20},
+		},
+		{
+			name:       "all explicit",
+			specs:      []base.StoreSpec{explicit(300 << 20), mem},
+			capacities: []int64{100 << 30, 0},
+			exp:        []int64{0, 0},
+		},
+		{
+			name:       "explicit rates use up the budget",
+			specs:      []base.StoreSpec{explicit(300 << 20), disk},
+			capacities: []int64{100 << 30, 100 << 30},
+			expErr:     "leaves nothing for 1 store(s)",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			shares, err := divideProvisionedBandwidth(300<<20, tc.specs, tc.capacities)
+			if tc.expErr != "" {
+				require.ErrorContains(t, err, tc.expErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.exp, shares)
+		})
+	}
+
+	_, err := divideProvisionedBandwidth(1, []base.StoreSpec{disk, disk}, []int64{0, 0})
+	require.ErrorContains(t, err, "too small to be divided among 2 store(s)")
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	cfg.TotalProvisionedBandwidth = -1
+	require.ErrorContains(t, cfg.validateTotalProvisionedBandwidth(), "must be non-negative")
+	cfg.TotalProvisionedBandwidth = 0
+	require.NoError(t, cfg.validateTotalProvisionedBandwidth())
+}
+
+// TestCreateEnginesTotalProvisionedBandwidth verifies that CreateEngines gives
+// the store without an explicit provisioned-rate what remains of the node
+// budget, without modifying the store specs.
+func TestCreateEnginesTotalProvisionedBandwidth(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	cfg := MakeConfig(ctx, cluster.MakeTestingClusterSettings())
+	cfg.TotalProvisionedBandwidth = 300 << 20
+	cfg.Stores.Specs = []base.StoreSpec{
+		{
+			Path:                filepath.Join(dir, "explicit"),
+			ProvisionedRateSpec: base.ProvisionedRateSpec{ProvisionedBandwidth: 100 << 20},
+		},
+		{Path: filepath.Join(dir, "shared")},
+	}
+	engines, err := cfg.CreateEngines(ctx)
+	require.NoError(t, err)
+	defer engines.Close()
+
+	var rates []int64
+	for _, spec := range cfg.admissionStoreSpecs() {
+		rates = append(rates, spec.ProvisionedRateSpec.ProvisionedBandwidth)
+	}
+	require.Equal(t, []int64{100 << 20, 200 << 20}, rates)
+	require.Zero(t, cfg.Stores.Specs[1].ProvisionedRateSpec.ProvisionedBandwidth)
+}
+
+func TestRecommendedScanInterval(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	require.Equal(t, minRecommendedScanInterval, cfg.RecommendedScanInterval(0))
+	require.Equal(t, minRecommendedScanInterval, cfg.RecommendedScanInterval(100))
+	require.Equal(t, defaultScanInterval, cfg.RecommendedScanInterval(10000))
+	require.Equal(t, maxRecommendedScanInterval, cfg.RecommendedScanInterval(10000000))
+	require.Equal(t, maxRecommendedScanInterval, cfg.RecommendedScanInterval(math.MaxInt))
+
+	prev := time.Duration(0)
+	for _, n := range []int{1, 1000, 5000, 10000, 100000, 1000000, 10000000} {
+		interval := cfg.RecommendedScanInterval(n)
+		require.GreaterOrEqual(t, interval, prev, "%d ranges", n)
+		require.GreaterOrEqual(t, interval, minRecommendedScanInterval)
+		require.LessOrEqual(t, interval, maxRecommendedScanInterval)
+		prev = interval
+	}
+	require.Greater(t, cfg.RecommendedScanInterval(100000), cfg.RecommendedScanInterval(10000))
+
+	// The scanner can't visit ranges faster than ScanMinIdleTime.
+	cfg.ScanMinIdleTime = time.Second
+	require.Equal(t, 10000*time.Second, cfg.RecommendedScanInterval(10000))
+}
+
+func TestPebbleOptionsFor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	cfg := MakeConfig(ctx, cluster.MakeTestingClusterSettings())
+	cfg.CacheSize = 64 << 20
+	cfg.Stores = base.StoreSpecList{Specs: []base.StoreSpec{
+		{InMemory: true, Size: base.SizeSpec{InBytes: base.MinimumStoreSize * 100}},
+		{InMemory: true, Size: base.SizeSpec{InBytes: base.MinimumStoreSize * 100}},
+	}}
+
+	_, err := cfg.PebbleOptionsFor(0)
+	require.Error(t, err)
+
+	engines, err := cfg.CreateEngines(ctx)
+	require.NoError(t, err)
+	defer engines.Close()
+
+	for i := range cfg.Stores.Specs {
+		opts, err := cfg.PebbleOptionsFor(i)
+		require.NoError(t, err)
+		require.Contains(t, opts, "[Options]")
+		require.Contains(t, opts, fmt.Sprintf("cache_size=%d", cfg.CacheSize))
+		require.Contains(t, opts, "compression=Snappy")
+	}
+	_, err = cfg.PebbleOptionsFor(len(cfg.Stores.Specs))
+	require.Error(t, err)
+}
+
+func TestSimulateStoreFailure(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	cfg := MakeConfig(ctx, cluster.MakeTestingClusterSettings())
+	cfg.TestingKnobs.Server = &TestingKnobs{StoreFailureInjection: true}
+	cfg.Stores = base.StoreSpecList{Specs: []base.StoreSpec{
+		{InMemory: true, Size: base.SizeSpec{InBytes: base.MinimumStoreSize * 100}},
+		{InMemory: true, Size: base.SizeSpec{InBytes: base.MinimumStoreSize * 100}},
+	}}
+	require.ErrorContains(t, cfg.SimulateStoreFailure(0), "engines not created yet")
+
+	engines, err := cfg.CreateEngines(ctx)
+	require.NoError(t, err)
+	defer engines.Close()
+
+	probe := func(eng storage.Engine, name string) error {
+		f, err := eng.Env().Create(name, vfs.WriteCategoryUnspecified)
+		if err != nil {
+			return err
+		}
+		return f.Close()
+	}
+	for i, eng := range engines {
+		require.NoError(t, probe(eng, fmt.Sprintf("before-%d", i)))
+	}
+
+	require.NoError(t, cfg.SimulateStoreFailure(1))
+	// Heal the store before the deferred close, which fails on an error in
+	// test builds.
+	defer cfg.storeFailures[1].Off()
+	require.NoError(t, probe(engines[0], "after-0"))
+	require.ErrorIs(t, probe(engines[1], "after-1"), errorfs.ErrInjected)
+
+	require.ErrorContains(t, cfg.SimulateStoreFailure(2), "out of range")
+}
+
+func TestSimulateStoreFailureRequiresKnob(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	cfg := MakeConfig(ctx, cluster.MakeTestingClusterSettings())
+	cfg.Stores = base.StoreSpecList{Specs: []base.StoreSpec{
+		{InMemory: true, Size: base.SizeSpec{InBytes: base.MinimumStoreSize * 100}},
+	}}
+	engines, err := cfg.CreateEngines(ctx)
+	require.NoError(t, err)
+	defer engines.Close()
+	require.ErrorContains(t, cfg.SimulateStoreFailure(0), "does not support failure injection")
+}
+
+func TestEncryptedStores(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	require.Empty(t, cfg.EncryptedStores())
+
+	cfg.Stores = base.StoreSpecList{Specs: []base.StoreSpec{
+		{Path: "/mnt/a", EncryptionOptions: []byte("key")},
+		{Path: "/mnt/b"},
+		{Path: "/mnt/c", EncryptionOptions: []byte("key")},
+		{InMemory: true, Size: base.SizeSpec{InBytes: base.MinimumStoreSize * 100}},
+	}}
+	require.Equal(t, []int{0, 2}, cfg.EncryptedStores())
+}
+
+func TestValidateQueueConcurrency(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	require.NoError(t, cfg.validateQueueConcurrency())
+
+	cfg.QueueConcurrency = map[string]int{"mvccGC": 4, "split": 1}
+	require.NoError(t, cfg.validateQueueConcurrency())
+	require.Equal(t, map[string]int{"mvccGC": 4, "split": 1}, cfg.QueueConcurrency)
+
+	cfg.QueueConcurrency = map[string]int{"gc": 4}
+	require.ErrorContains(t, cfg.validateQueueConcurrency(), `unknown queue "gc"`)
+
+	cfg.QueueConcurrency = map[string]int{"merge": 0}
+	require.ErrorContains(t, cfg.validateQueueConcurrency(), `must be at least 1`)
+}
+
+func TestEstimatedStartupMemory(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	cfg.CacheSize = 1 << 30
+	cfg.MemoryPoolSize = 2 << 30
+	cfg.Stores = base.StoreSpecList{Specs: []base.StoreSpec{
+		{Path: "/mnt/data1"},
+		{Path: "/mnt/data2", PebbleOptions: "[Options]\nmem_table_size=16777216\nmem_table_stop_writes_threshold=2"},
+		{InMemory: true, Size: base.SizeSpec{InBytes: 512 << 20}},
+		{Path: "/mnt/data3", CacheSize: 256 << 20},
+	}}
+
+	const defaultMemTables = 4 * (64 << 20)
+	expected := uint64(1<<30) + // cache
+		uint64(2<<30) + // SQL memory pool
+		defaultMemTables + // first store
+		2*(16<<20) + // second store, with overridden memtable options
+		defaultMemTables + (512 << 20) + // in-memory store
+		defaultMemTables + (256 << 20) + // third store, with a cache of its own
+		startupMemoryOverhead
+	require.Equal(t, expected, cfg.EstimatedStartupMemory())
+}
+
+func TestValidateAddressableMemory(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const addressable32Bit = 1<<32 - 1
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	cfg.CacheSize = 1 << 30
+	cfg.MemoryPoolSize = 1 << 30
+	require.NoError(t, cfg.validateAddressableMemory(addressable32Bit))
+	require.NoError(t, cfg.validateAddressableMemory(maxAddressableMemory))
+
+	cfg.CacheSize = 4 << 30
+	require.ErrorContains(t, cfg.validateAddressableMemory(addressable32Bit),
+		"more than the 4.0 GiB this process can address")
+	if maxAddressableMemory > addressable32Bit {
+		require.NoError(t, cfg.validateAddressableMemory(maxAddressableMemory))
+	}
+}
+
+func TestValidateDefaultHashShardedBuckets(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	for _, tc := range []struct {
+		buckets int
+		valid   bool
+	}{
+		{0, true}, {2, true}, {16, true}, {2048, true},
+		{-1, false}, {1, false}, {2049, false},
+	} {
+		cfg.DefaultHashShardedBuckets = tc.buckets
+		if err := cfg.validateDefaultHashShardedBuckets(); tc.valid {
+			require.NoError(t, err, "buckets: %d", tc.buckets)
+		} else {
+			require.Error(t, err, "buckets: %d", tc.buckets)
+		}
+	}
+}
+
+func TestRaftTimeoutsConfig(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	cfg.RaftTickInterval = 2 * time.Second
+	cfg.RaftElectionTimeoutTicks = 10
+	cfg.RaftConfig.SetDefaults()
+	require.Equal(t, 2*time.Second, cfg.RaftTickInterval)
+	require.Equal(t, 10, cfg.RaftElectionTimeoutTicks)
+	require.Equal(t, 20*time.Second, cfg.RaftElectionTimeout())
+	require.NoError(t, cfg.validateRaftTimeouts())
+
+	cfg.RaftElectionTimeoutTicks = cfg.RaftHeartbeatIntervalTicks
+	require.ErrorContains(t, cfg.validateRaftTimeouts(), "must exceed the heartbeat interval")
+	require.ErrorContains(t, cfg.InitNode(context.Background()), "must exceed the heartbeat interval")
+
+	cfg.RaftElectionTimeoutTicks = 10
+	cfg.RaftTickInterval = -time.Second
+	require.ErrorContains(t, cfg.validateRaftTimeouts(), "tick interval must be positive")
+}
+
+func TestValidateBootstrapBackupSchedule(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	defer func(d string) { build.Distribution = d }(build.Distribution)
+	build.Distribution = "CCL"
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	require.NoError(t, cfg.validateBootstrapBackupSchedule())
+
+	cfg.BootstrapBackupSchedule = "@daily"
+	require.ErrorContains(t, cfg.validateBootstrapBackupSchedule(), "must be set together")
+	cfg.BootstrapBackupURI = "s3://bucket/backups?AWS_SECRET_ACCESS_KEY=secret"
+	require.NoError(t, cfg.validateBootstrapBackupSchedule())
+	cfg.BootstrapBackupSchedule = "0 3 * * *"
+	require.NoError(t, cfg.validateBootstrapBackupSchedule())
+
+	cfg.BootstrapBackupSchedule = "every day"
+	require.ErrorContains(t, cfg.validateBootstrapBackupSchedule(), "invalid bootstrap backup schedule")
+
+	// The URI, which may hold credentials, is not part of the error.
+	cfg.BootstrapBackupSchedule = "@daily"
+	for _, uri := range []string{"/backups?secret", "://secret", "%zzsecret"} {
+		cfg.BootstrapBackupURI = uri
+		err := cfg.validateBootstrapBackupSchedule()
+		require.ErrorContains(t, err, "invalid bootstrap backup URI", uri)
+		require.NotContains(t, err.Error(), "secret")
+	}
+	// OSS binaries cannot create the schedule.
+	cfg.BootstrapBackupURI = "nodelocal://1/backups"
+	require.NoError(t, cfg.validateBootstrapBackupSchedule())
+	build.Distribution = "OSS"
+	require.ErrorContains(t, cfg.validateBootstrapBackupSchedule(), "requires a CCL binary")
+}
+
+func TestValidate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	require.NoError(t, cfg.Validate())
+
+	// A single violation is reported as is.
+	cfg.MaxOffset = MaxOffsetType(10 * time.Minute)
+	err := cfg.Validate()
+	require.Equal(t, cfg.validateMaxOffset().Error(), err.Error())
+
+	// Multiple violations are all reported, one per line.
+	cfg.PGSSLMode = "verify-host"
+	cfg.DefaultTimeZone = "Mars/Olympus_Mons"
+	err = cfg.Validate()
+	require.Error(t, err)
+	lines := strings.Split(err.Error(), "\n")
+	require.Len(t, lines, 3, err.Error())
+	require.Contains(t, lines[0], `invalid PG sslmode "verify-host"`)
+	require.Contains(t, lines[1], "max clock offset must be between 0 and 5s")
+	require.Contains(t, lines[2], `invalid default time zone "Mars/Olympus_Mons"`)
+
+	// InitNode reports the same violations.
+	require.Equal(t, err.Error(), cfg.InitNode(context.Background()).Error())
+}
+
+// TestValidateFields checks the validations of individual configuration
+// fields. Each accepts the default and a valid value, and rejects an invalid
+// one with the expected error.
+func TestValidateFields(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testCases := []struct {
+		name        string
+		validate    func(*Config) error
+		setValid    func(*Config)
+		setInvalid  func(*Config)
+		expectedErr string
+	}{
+		{
+			name:        "MaxConnsPerIP",
+			validate:    func(cfg *Config) error { return cfg.validateMaxConnsPerIP() },
+			setValid:    func(cfg *Config) { cfg.MaxConnsPerIP = 10 },
+			setInvalid:  func(cfg *Config) { cfg.MaxConnsPerIP = -1 },
+			expectedErr: "maximum connections per IP must be non-negative, got -1",
+		},
+		{
+			name:        "DefaultMaxIntentsBytes",
+			validate:    func(cfg *Config) error { return cfg.validateDefaultMaxIntentsBytes() },
+			setValid:    func(cfg *Config) { cfg.DefaultMaxIntentsBytes = 1 << 20 },
+			setInvalid:  func(cfg *Config) { cfg.DefaultMaxIntentsBytes = -1 },
+			expectedErr: "default max intents bytes must be non-negative, got -1",
+		},
+		{
+			name:        "DescriptorLeaseDuration",
+			validate:    func(cfg *Config) error { return cfg.validateDescriptorLeaseDuration() },
+			setValid:    func(cfg *Config) { cfg.DescriptorLeaseDuration = 10 * time.Minute },
+			setInvalid:  func(cfg *Config) { cfg.DescriptorLeaseDuration = -time.Second },
+			expectedErr: "descriptor lease duration must be in range [0, 1h0m0s], got -1s",
+		},
+		{
+			name:        "DescriptorLeaseDuration/too long",
+			validate:    func(cfg *Config) error { return cfg.validateDescriptorLeaseDuration() },
+			setValid:    func(cfg *Config) { cfg.DescriptorLeaseDuration = 10 * time.Minute },
+			setInvalid:  func(cfg *Config) { cfg.DescriptorLeaseDuration = 24 * time.Hour },
+			expectedErr: "descriptor lease duration must be in range [0, 1h0m0s], got 24h0m0s",
+		},
+		{
+			name:        "JoinRebalanceRate",
+			validate:    func(cfg *Config) error { return cfg.validateJoinRebalanceRate() },
+			setValid:    func(cfg *Config) { cfg.JoinRebalanceRate = 0.5 },
+			setInvalid:  func(cfg *Config) { cfg.JoinRebalanceRate = -1 },
+			expectedErr: "join rebalance rate must be non-negative, got -1",
+		},
+		{
+			name:        "RPCServerConcurrency",
+			validate:    func(cfg *Config) error { return cfg.validateRPCServerConcurrency() },
+			setValid:    func(cfg *Config) { cfg.RPCServerConcurrency = 16 },
+			setInvalid:  func(cfg *Config) { cfg.RPCServerConcurrency = -1 },
+			expectedErr: "RPC server concurrency must be non-negative, got -1",
+		},
+		{
+			name:        "SlowQueryLogThreshold",
+			validate:    func(cfg *Config) error { return cfg.validateSlowQueryLogThreshold() },
+			setValid:    func(cfg *Config) { cfg.SlowQueryLogThreshold = time.Second },
+			setInvalid:  func(cfg *Config) { cfg.SlowQueryLogThreshold = -time.Second },
+			expectedErr: "slow query log threshold must be non-negative, got -1s",
+		},
+		{
+			name:        "DefaultGCTTL",
+			validate:    func(cfg *Config) error { return cfg.validateDefaultGCTTL() },
+			setValid:    func(cfg *Config) { cfg.DefaultGCTTL = 25 * time.Hour },
+			setInvalid:  func(cfg *Config) { cfg.DefaultGCTTL = -time.Hour },
+			expectedErr: "default GC TTL must be zero or at least 1s, got -1h0m0s",
+		},
+		{
+			name:        "DefaultGCTTL/too short",
+			validate:    func(cfg *Config) error { return cfg.validateDefaultGCTTL() },
+			setValid:    func(cfg *Config) { cfg.DefaultGCTTL = 25 * time.Hour },
+			setInvalid:  func(cfg *Config) { cfg.DefaultGCTTL = time.Millisecond },
+			expectedErr: "default GC TTL must be zero or at least 1s, got 1ms",
+		},
+		{
+			name:        "ExpectedClusterSize",
+			validate:    func(cfg *Config) error { return cfg.validateExpectedClusterSize() },
+			setValid:    func(cfg *Config) { cfg.ExpectedClusterSize = 500 },
+			setInvalid:  func(cfg *Config) { cfg.ExpectedClusterSize = -1 },
+			expectedErr: "expected cluster size must be non-negative, got -1",
+		},
+		{
+			name:        "AutoStatsMinStaleRows",
+			validate:    func(cfg *Config) error { return cfg.validateAutoStatsMinStaleRows() },
+			setValid:    func(cfg *Config) { cfg.AutoStatsMinStaleRows = 1000 },
+			setInvalid:  func(cfg *Config) { cfg.AutoStatsMinStaleRows = -1 },
+			expectedErr: "auto stats min stale rows must be non-negative, got -1",
+		},
+		{
+			name:        "MaxNewConnsPerSecond",
+			validate:    func(cfg *Config) error { return cfg.validateMaxNewConnsPerSecond() },
+			setValid:    func(cfg *Config) { cfg.MaxNewConnsPerSecond = 100 },
+			setInvalid:  func(cfg *Config) { cfg.MaxNewConnsPerSecond = -1 },
+			expectedErr: "maximum new connections per second must be non-negative, got -1",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+			require.NoError(t, tc.validate(&cfg))
+			tc.setValid(&cfg)
+			require.NoError(t, tc.validate(&cfg))
+			tc.setInvalid(&cfg)
+			require.EqualError(t, tc.validate(&cfg), tc.expectedErr)
+		})
+	}
+}
+
+func TestValidateMaxOffset(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	require.NoError(t, cfg.validateMaxOffset())
+	for _, mo := range []time.Duration{0, 250 * time.Millisecond, maximumMaxClockOffset} {
+		cfg.MaxOffset = MaxOffsetType(mo)
+		require.NoError(t, cfg.validateMaxOffset(), mo)
+	}
+
+	cfg.MaxOffset = MaxOffsetType(10 * time.Minute)
+	require.ErrorContains(t, cfg.validateMaxOffset(), "max clock offset must be between 0 and 5s, got 10m0s")
+	require.ErrorContains(t, cfg.InitNode(context.Background()), "max clock offset")
+	cfg.MaxOffset = MaxOffsetType(-time.Second)
+	require.Error(t, cfg.validateMaxOffset())
+}
+
+func TestValidateRaftLogTruncationThreshold(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	require.NoError(t, cfg.validateRaftLogTruncationThreshold())
+	cfg.RaftLogTruncationThreshold = 0
+	require.NoError(t, cfg.validateRaftLogTruncationThreshold())
+	cfg.RaftLogTruncationThreshold = 64 << 20
+	require.NoError(t, cfg.validateRaftLogTruncationThreshold())
+
+	cfg.RaftLogTruncationThreshold = 4 << 10
+	require.ErrorContains(t, cfg.validateRaftLogTruncationThreshold(), "must be at least 1.0 MiB")
+	cfg.RaftLogTruncationThreshold = -1
+	require.Error(t, cfg.validateRaftLogTruncationThreshold())
+}
+
+func TestAuditLogConfig(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	dir := t.TempDir()
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	cfg.AuditLogDir = filepath.Join(dir, "audit")
+	cfg.AuditLogMaxSize = 1 << 20
+	cfg.AuditLogMaxFiles = 4
+	require.NoError(t, cfg.validateAuditLog())
+	// Validation leaves the filesystem alone.
+	require.NoDirExists(t, cfg.AuditLogDir)
+
+	h := logconfig.Holder{Config: logconfig.DefaultConfig()}
+	require.NoError(t, h.Set(`sinks: {file-groups: {sql-audit: {channels: SENSITIVE_ACCESS}}}`))
+	require.NoError(t, cfg.ConfigureAuditLog(&h.Config))
+	require.DirExists(t, cfg.AuditLogDir)
+	fc := h.Config.Sinks.FileGroups[auditLogFileGroup]
+	require.Equal(t, cfg.AuditLogDir, *fc.Dir)
+	require.Equal(t, logconfig.ByteSize(1<<20), *fc.MaxFileSize)
+	require.Equal(t, logconfig.ByteSize(4<<20), *fc.MaxGroupSize)
+
+	// A directory which cannot be created is rejected.
+	file := filepath.Join(dir, "file")
+	require.NoError(t, os.WriteFile(file, nil, 0644))
+	cfg.AuditLogDir = filepath.Join(file, "audit")
+	require.NoError(t, cfg.validateAuditLog())
+	require.ErrorContains(t, cfg.ConfigureAuditLog(&h.Config), "audit log directory")
+
+	cfg.AuditLogDir = ""
+	cfg.AuditLogMaxSize = -1
+	require.ErrorContains(t, cfg.validateAuditLog(), "max size must be non-negative")
+	cfg.AuditLogMaxSize = 0
+	cfg.AuditLogMaxFiles = -1
+	require.ErrorContains(t, cfg.validateAuditLog(), "max files must be non-negative")
+	cfg.AuditLogMaxFiles = 4
+	require.ErrorContains(t, cfg.validateAuditLog(), "requires audit log max size")
+}
+
+func TestValidateDrainAllowedUsers(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	require.NoError(t, cfg.validateDrainAllowedUsers())
+	cfg.DrainAllowedUsers = []string{"root", "ops_user"}
+	require.NoError(t, cfg.validateDrainAllowedUsers())
+	cfg.DrainAllowedUsers = []string{"ops user"}
+	require.ErrorContains(t, cfg.validateDrainAllowedUsers(), `invalid drain allowed user "ops user"`)
+}
+
+func TestValidateMaxSessionResultBytes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	require.NoError(t, cfg.validateMaxSessionResultBytes())
+	cfg.MaxSessionResultBytes = cfg.MemoryPoolSize / 2
+	require.NoError(t, cfg.validateMaxSessionResultBytes())
+	cfg.MaxSessionResultBytes = -1
+	require.Error(t, cfg.validateMaxSessionResultBytes())
+	cfg.MaxSessionResultBytes = cfg.MemoryPoolSize
+	require.ErrorContains(t, cfg.validateMaxSessionResultBytes(), "SQL memory pool size")
+}
+
+func TestValidateJoinDiversity(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	locality := func(s string) roachpb.Locality {
+		var l roachpb.Locality
+		require.NoError(t, l.Set(s))
+		return l
+	}
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	cfg.Locality = locality("region=us-east1,zone=us-east1-b")
+
+	setPeers := func(peers ...roachpb.Locality) {
+		cfg.peerLocalities = func() []roachpb.Locality { return peers }
+	}
+
+	// Nothing is known about the peers before the server provides them.
+	require.Empty(t, cfg.ValidateJoinDiversity())
+
+	// Peers all in this node's zone.
+	setPeers(
+		locality("region=us-east1,zone=us-east1-b"),
+		locality("region=us-east1,zone=us-east1-b"),
+		roachpb.Locality{},
+	)
+	warnings := cfg.ValidateJoinDiversity()
+	require.Len(t, warnings, 1)
+	require.Contains(t, warnings[0], "all 2 known peers are in locality region=us-east1,zone=us-east1-b")
+
+	// A peer in another zone provides diversity.
+	setPeers(
+		locality("region=us-east1,zone=us-east1-b"),
+		locality("region=us-east1,zone=us-east1-c"),
+	)
+	require.Empty(t, cfg.ValidateJoinDiversity())
+
+	// Nothing is known about the peers.
+	setPeers()
+	require.Empty(t, cfg.ValidateJoinDiversity())
+	setPeers(roachpb.Locality{})
+	require.Empty(t, cfg.ValidateJoinDiversity())
+
+	// Nothing is known about this node.
+	cfg.Locality = roachpb.Locality{}
+	setPeers(locality("region=us-east1,zone=us-east1-b"))
+	require.Empty(t, cfg.ValidateJoinDiversity())
+}
+
+func TestSystemRangeReplicationFactor(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	require.Equal(t, 5, cfg.SystemRangeReplicationFactor())
+
+	cfg.DefaultSystemZoneConfig.NumReplicas = proto.Int32(3)
+	require.Equal(t, 3, cfg.SystemRangeReplicationFactor())
+
+	cfg.DefaultSystemZoneConfig.NumReplicas = nil
+	require.Equal(t, 5, cfg.SystemRangeReplicationFactor())
+}
+
+func TestValidateDefaultDistSQLMode(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	require.NoError(t, cfg.validateDefaultDistSQLMode())
+	for _, mode := range []string{"off", "auto", "on"} {
+		cfg.DefaultDistSQLMode = mode
+		require.NoError(t, cfg.validateDefaultDistSQLMode())
+	}
+	for _, mode := range []string{"always", "ON", "sometimes"} {
+		cfg.DefaultDistSQLMode = mode
+		require.Error(t, cfg.validateDefaultDistSQLMode())
+	}
+}
+
+func TestValidateDefaultTimeZone(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	require.NoError(t, cfg.validateDefaultTimeZone())
+	cfg.DefaultTimeZone = "Europe/Amsterdam"
+	require.NoError(t, cfg.validateDefaultTimeZone())
+	cfg.DefaultTimeZone = "Mars/Olympus_Mons"
+	require.Error(t, cfg.validateDefaultTimeZone())
+}
+
+// TestCreateEnginesInMemoryPercentage verifies that in-memory stores sized as
+// a percentage are resolved against the total memory of the process.
+func TestCreateEnginesInMemoryPercentage(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	defer func(prev func(context.Context) (int64, error)) { totalMemory = prev }(totalMemory)
+	totalMemory = func(context.Context) (int64, error) { return 8 << 30, nil }
+
+	createEngines := func(percent float64) (Engines, error) {
+		cfg := MakeConfig(ctx, cluster.MakeTestingClusterSettings())
+		cfg.Stores.Specs = []base.StoreSpec{{InMemory: true, Size: base.SizeSpec{Percent: percent}}}
+		return cfg.CreateEngines(ctx)
+	}
+
+	engines, err := createEngines(25)
+	require.NoError(t, err)
+	defer engines.Close()
+	capacity, err := engines[0].Capacity()
+	require.NoError(t, err)
+	require.Equal(t, int64(2<<30), capacity.Capacity)
+
+	_, err = createEngines(0.0001)
+	require.ErrorContains(t, err, "which is below the minimum requirement")
+
+	totalMemory = func(context.Context) (int64, error) { return 100, nil }
+	_, err = createEngines(0.5)
+	require.ErrorContains(t, err, "rounds to zero bytes")
+}
+
+func TestValidateInMemoryStoreSizes(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	defer func(prev func(context.Context) (int64, error)) { totalMemory = prev }(totalMemory)
+	totalMemory = func(context.Context) (int64, error) { return 32 << 30, nil }
+
+	cfg := MakeConfig(ctx, cluster.MakeTestingClusterSettings())
+	cfg.Stores.Specs = []base.StoreSpec{
+		{InMemory: true, Size: base.SizeSpec{InBytes: 8 << 30}},
+		{InMemory: true, Size: base.SizeSpec{Percent: 50}},
+	}
+	require.NoError(t, cfg.validateInMemoryStoreSizes(ctx))
+	cfg.StrictInMemoryStoreSize = true
+	require.NoError(t, cfg.validateInMemoryStoreSizes(ctx))
+
+	// The stores add up to 24 GiB, which exceeds half of the memory.
+	cfg.InMemoryStoreMemoryFraction = 0.5
+	require.ErrorContains(t, cfg.validateInMemoryStoreSizes(ctx),
+		"the 2 in-memory stores add up to 24 GiB, more than 50% of the 32 GiB of memory available")
+	cfg.StrictInMemoryStoreSize = false
+	require.NoError(t, cfg.validateInMemoryStoreSizes(ctx))
+
+	// The default threshold is all of the memory.
+	cfg.InMemoryStoreMemoryFraction = 0
+	cfg.StrictInMemoryStoreSize = true
+	cfg.Stores.Specs = []base.StoreSpec{{InMemory: true, Size: base.SizeSpec{InBytes: 500 << 30}}}
+	require.ErrorContains(t, cfg.validateInMemoryStoreSizes(ctx), "more than 100% of the 32 GiB")
+
+	cfg.InMemoryStoreMemoryFraction = -1
+	require.Error(t, cfg.validateInMemoryStoreSizes(ctx))
+}
+
+// TestCreateEnginesStoreCacheSize verifies that on-disk stores with a cache of
+// their own can be opened alongside stores sharing the node-wide cache.
+func TestCreateEnginesStoreCacheSize(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	cfg := MakeConfig(ctx, cluster.MakeTestingClusterSettings())
+	cfg.Stores.Specs = []base.StoreSpec{
+		{Path: filepath.Join(dir, "ssd"), CacheSize: 64 << 20},
+		{Path: filepath.Join(dir, "hdd1")},
+		{Path: filepath.Join(dir, "hdd2")},
+	}
+	engines, err := cfg.CreateEngines(ctx)
+	require.NoError(t, err)
+	defer engines.Close()
+	require.Len(t, engines, 3)
+}
+
+func TestValidateMinTLSVersion(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	require.NoError(t, cfg.validateMinTLSVersion())
+	cfg.MinTLSVersion = "1.3"
+	require.NoError(t, cfg.validateMinTLSVersion())
+	cfg.MinTLSVersion = "1.0"
+	require.ErrorContains(t, cfg.validateMinTLSVersion(), `unsupported TLS version "1.0"`)
+}
+
+func TestValidateStorePaths(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	setStores := func(specs ...base.StoreSpec) {
+		cfg.Stores = base.StoreSpecList{Specs: specs}
+	}
+	mem := base.StoreSpec{InMemory: true, Size: base.SizeSpec{InBytes: base.MinimumStoreSize}}
+
+	setStores(base.StoreSpec{Path: "/mnt/a"}, base.StoreSpec{Path: "/mnt/ab"}, mem, mem)
+	require.NoError(t, cfg.validateStorePaths())
+
+	setStores(base.StoreSpec{Path: "/mnt/d1"}, base.StoreSpec{Path: "/mnt/d1/"})
+	require.ErrorContains(t, cfg.validateStorePaths(), `stores "path=/mnt/d1" and "path=/mnt/d1/" use the same directory /mnt/d1`)
+
+	setStores(base.StoreSpec{Path: "/mnt/a"}, base.StoreSpec{Path: "/mnt/a/b"})
+	require.ErrorContains(t, cfg.validateStorePaths(), `stores "path=/mnt/a" and "path=/mnt/a/b" have nested directories`)
+	setStores(base.StoreSpec{Path: "/mnt/a/b"}, base.StoreSpec{Path: "/mnt/a"})
+	require.ErrorContains(t, cfg.validateStorePaths(), "have nested directories")
+
+	// Relative paths are resolved against the working directory.
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	setStores(base.StoreSpec{Path: "data"}, base.StoreSpec{Path: filepath.Join(wd, "data")})
+	require.ErrorContains(t, cfg.validateStorePaths(), "use the same directory")
+}
+
+func TestFirstDiskStoreIndex(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	cfg.Stores.Specs = []base.StoreSpec{{InMemory: true}}
+	_, ok := cfg.FirstDiskStoreIndex()
+	require.False(t, ok)
+
+	cfg.Stores.Specs = append(cfg.Stores.Specs, base.StoreSpec{Path: "/mnt/data1"}, base.StoreSpec{Path: "/mnt/data2"})
+	i, ok := cfg.FirstDiskStoreIndex()
+	require.True(t, ok)
+	require.Equal(t, 1, i)
+}
+
+func TestValidateAdmissionConfig(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	for _, tc := range []struct {
+		cfg    AdmissionConfig
+		expErr string
+	}{
+		{cfg: AdmissionConfig{}},
+		{cfg: AdmissionConfig{ElasticCPUMinUtilization: 0.1, ElasticCPUMaxUtilization: 0.5}},
+		{cfg: AdmissionConfig{ElasticCPUMaxUtilization: 0.5}},
+		{cfg: AdmissionConfig{ElasticCPUMinUtilization: 0.6, ElasticCPUMaxUtilization: 0.5}, expErr: "must not exceed"},
+		// The floor is compared against the default ceiling.
+		{cfg: AdmissionConfig{ElasticCPUMinUtilization: 0.9}, expErr: "must not exceed"},
+		{cfg: AdmissionConfig{ElasticCPUMaxUtilization: 2}, expErr: "invalid elastic CPU max utilization"},
+	} {
+		err := tc.cfg.validate()
+		if tc.expErr == "" {
+			require.NoError(t, err, "%+v", tc.cfg)
+		} else {
+			require.ErrorContains(t, err, tc.expErr, "%+v", tc.cfg)
+		}
+	}
+}
+
+func TestLocalityString(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	require.Equal(t, "", cfg.LocalityString())
+
+	require.NoError(t, cfg.Locality.Set("zone=b,region=us-east1"))
+	require.Equal(t, "zone=b,region=us-east1", cfg.LocalityString())
+
+	cfg.Attrs = "ssd::fast"
+	require.Equal(t, "zone=b,region=us-east1,ssd,fast", cfg.LocalityString())
+}
+
+func TestCheckCertExpiry(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	// The certificates are generated on disk rather than embedded.
+	securityassets.ResetLoader()
+	defer securityassets.SetLoader(securitytest.EmbeddedAssets)
+
+	ctx := context.Background()
+	cfg := MakeConfig(ctx, cluster.MakeTestingClusterSettings())
+	cfg.Insecure = false
+	cfg.SSLCertsDir = t.TempDir()
+	caKey := filepath.Join(t.TempDir(), "ca.key")
+
+	// An empty certificates directory is not an error.
+	require.NoError(t, cfg.CheckCertExpiry(ctx, time.Hour))
+
+	// A certificate expiring within the window is only warned about.
+	require.NoError(t, security.CreateCAPair(
+		cfg.SSLCertsDir, caKey, 2048, time.Hour, false /* allowKeyReuse */, false /* overwrite */))
+	require.NoError(t, cfg.CheckCertExpiry(ctx, 24*time.Hour))
+
+	// An expired certificate is an error.
+	require.NoError(t, security.CreateCAPair(
+		cfg.SSLCertsDir, caKey, 2048, -time.Hour, true /* allowKeyReuse */, true /* overwrite */))
+	require.ErrorContains(t, cfg.CheckCertExpiry(ctx, 24*time.Hour), "expired")
+
+	// Insecure mode does not look at certificates.
+	cfg.Insecure = true
+	require.NoError(t, cfg.CheckCertExpiry(ctx, 24*time.Hour))
+}
+
+func TestGenerateDevCerts(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	// The certificates are generated on disk rather than embedded.
+	securityassets.ResetLoader()
+	defer securityassets.SetLoader(securitytest.EmbeddedAssets)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	cfg.Insecure = false
+	cfg.SSLCertsDir = ""
+	cfg.SQLAdvertiseAddr = "localhost:26257"
+	require.NoError(t, cfg.GenerateDevCerts())
+	certsDir := cfg.SSLCertsDir
+	require.NotEmpty(t, certsDir)
+	defer func() { _ = os.RemoveAll(certsDir) }()
+
+	cl := security.NewCertificateLoader(certsDir)
+	require.NoError(t, cl.Load())
+	usages := make(map[security.PemUsage]bool)
+	for _, ci := range cl.Certificates() {
+		require.NoError(t, ci.Error)
+		usages[ci.FileUsage] = true
+	}
+	require.Equal(t, map[security.PemUsage]bool{
+		security.CAPem: true, security.NodePem: true, security.ClientPem: true,
+	}, usages)
+
+	// The connection URL uses the generated files.
+	s, err := cfg.PGURLForTenant("root", 1)
+	require.NoError(t, err)
+	u, err := url.Parse(s)
+	require.NoError(t, err)
+	for param, filename := range map[string]string{
+		"sslrootcert": certnames.CACertFilename(),
+		"sslcert":     certnames.ClientCertFilename(username.RootUserName()),
+		"sslkey":      certnames.ClientKeyFilename(username.RootUserName()),
+	} {
+		path := u.Query().Get(param)
+		require.Equal(t, filepath.Join(certsDir, filename), path, param)
+		require.FileExists(t, path)
+	}
+
+	// Existing certificates are not clobbered.
+	caCert, err := os.ReadFile(filepath.Join(certsDir, certnames.CACertFilename()))
+	require.NoError(t, err)
+	require.ErrorContains(t, cfg.GenerateDevCerts(), "already contains certificates")
+	caCertAfter, err := os.ReadFile(filepath.Join(certsDir, certnames.CACertFilename()))
+	require.NoError(t, err)
+	require.Equal(t, caCert, caCertAfter)
+
+	// Certificates are only generated for secure servers.
+	cfg.Insecure = true
+	cfg.SSLCertsDir = t.TempDir()
+	require.Error(t, cfg.GenerateDevCerts())
+}
+
+func TestValidateFilesystems(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	const mounts = `/dev/sda1 / ext4 rw,relatime 0 0
+tmpfs /tmp tmpfs rw,nosuid,nodev 0 0
+/dev/sdb1 /mnt/data xfs rw,noatime 0 0
+fileserver:/export /mnt/data/shared nfs4 rw,relatime 0 0
+/dev/sdc1 /mnt/my\040disk ext4 rw 0 0
+`
+	readMounts := func() ([]byte, error) { return []byte(mounts), nil }
+	allowed := []string{"ext4", "xfs"}
+
+	for _, tc := range []struct {
+		paths  []string
+		expErr string
+	}{
+		{paths: []string{"/var/lib/cockroach"}},
+		{paths: []string{"/mnt/data/cockroach", "/mnt/my disk/cockroach"}},
+		// A mount point does not contain sibling paths sharing its prefix.
+		{paths: []string{"/mnt/data/sharedx"}},
+		{paths: []string{"/tmp/cockroach"}, expErr: "store /tmp/cockroach resides on a tmpfs filesystem"},
+		{
+			paths:  []string{"/mnt/data/cockroach", "/mnt/data/shared/cockroach"},
+			expErr: "store /mnt/data/shared/cockroach resides on a nfs4 filesystem",
+		},
+	} {
+		t.Run(strings.Join(tc.paths, ","), func(t *testing.T) {
+			cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+			cfg.Stores.Specs = nil
+			for _, path := range tc.paths {
+				cfg.Stores.Specs = append(cfg.Stores.Specs, base.StoreSpec{Path: path})
+			}
+			// In-memory stores are not checked.
+			cfg.Stores.Specs = append(cfg.Stores.Specs, base.StoreSpec{InMemory: true})
+			err := cfg.validateFilesystems(allowed, readMounts)
+			if tc.expErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.expErr)
+			}
+		})
+	}
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	cfg.Stores.Specs = []base.StoreSpec{{Path: "/mnt/data/shared/cockroach"}}
+	require.NoError(t, cfg.validateFilesystems(append(allowed, "nfs4"), readMounts))
+
+	// Symlinks are resolved before looking up the filesystem.
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	target := filepath.Join(dir, "target")
+	require.NoError(t, os.Mkdir(target, 0755))
+	link := filepath.Join(dir, "link")
+	require.NoError(t, os.Symlink(target, link))
+	symlinkMounts := fmt.Sprintf("tmpfs / tmpfs rw 0 0\n/dev/sdb1 %s xfs rw 0 0\n", target)
+	cfg.Stores.Specs = []base.StoreSpec{{Path: link}}
+	require.NoError(t, cfg.validateFilesystems(allowed, func() ([]byte, error) {
+		return []byte(symlinkMounts), nil
+	}))
+}
+
+func TestMountedFilesystemType(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	// The mounts are listed with the nested mount points first.
+	const mounts = `fileserver:/export /mnt/data/shared nfs4 rw,relatime 0 0
+/dev/sdb1 /mnt/data xfs rw,noatime 0 0
+/dev/sda1 / ext4 rw,relatime 0 0
+/dev/sdc1 /mnt/stacked ext4 rw 0 0
+tmpfs /mnt/stacked tmpfs rw 0 0
+`
+	for path, exp := range map[string]string{
+		"/mnt/data/shared/cockroach": "nfs4",
+		"/mnt/data/cockroach":        "xfs",
+		"/var/lib/cockroach":         "ext4",
+		// The last of the mounts stacked on a mount point wins.
+		"/mnt/stacked/cockroach": "tmpfs",
+	} {
+		fsType, ok := mountedFilesystemType([]byte(mounts), path)
+		require.True(t, ok, path)
+		require.Equal(t, exp, fsType, path)
+	}
+	_, ok := mountedFilesystemType([]byte("/dev/sdb1 /mnt/data xfs rw 0 0\n"), "/var/lib")
+	require.False(t, ok)
+}
+
+func TestProbeConfig(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	cfg.SQLAdvertiseAddr = "db.example.com:26257"
+	cfg.SSLCertsDir = "/certs"
+
+	for _, insecure := range []bool{false, true} {
+		cfg.Insecure = insecure
+		probe := cfg.ProbeConfig()
+		require.Equal(t, "db.example.com:26257", probe.SQLAdvertiseAddr)
+		require.Equal(t, insecure, probe.Insecure)
+		require.Equal(t, "/certs", probe.SSLCertsDir)
+		// None of the server-only configuration is carried over.
+		require.Empty(t, probe.Addr)
+		require.Empty(t, probe.HTTPAddr)
+
+		clientConnOptions, serverParams := MakeServerOptionsForURL(probe)
+		u, err := clientsecopts.MakeURLForServer(clientConnOptions, serverParams, url.User("probe"))
+		require.NoError(t, err)
+		_, host, port := u.GetNetworking()
+		require.Equal(t, "db.example.com", host)
+		require.Equal(t, "26257", port)
+		tlsUsed, tlsMode, _ := u.GetTLSOptions()
+		require.Equal(t, !insecure, tlsUsed)
+		if !insecure {
+			require.Equal(t, pgurl.TLSVerifyFull, tlsMode)
+		}
+	}
+}
+
+func TestPGURLStruct(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	cfg.Insecure = true
+	cfg.SQLAdvertiseAddr = "db.example.com:26257"
+
+	u, err := cfg.PGURLStruct("app")
+	require.NoError(t, err)
+	require.Equal(t, "app", u.User.Username())
+	require.Equal(t, "db.example.com:26257", u.Host)
+	require.Equal(t, "disable", u.Query().Get("sslmode"))
+	s, err := cfg.PGURL("app")
+	require.NoError(t, err)
+	require.Equal(t, u.String(), s)
+
+	// Secure servers verify the server certificate.
+	cfg.Insecure = false
+	cfg.SSLCertsDir = t.TempDir()
+	u, err = cfg.PGURLStruct("app")
+	require.NoError(t, err)
+	require.Equal(t, "verify-full", u.Query().Get("sslmode"))
+
+	// Parameters can be added without re-parsing the URL.
+	q := u.Query()
+	q.Set("application_name", "probe")
+	u.RawQuery = q.Encode()
+	require.Equal(t, "probe", u.Query().Get("application_name"))
+	require.Equal(t, "verify-full", u.Query().Get("sslmode"))
+}
+
+func TestPGURLHost(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	cfg.Insecure = true
+	for _, tc := range []struct {
+		addr, expHost string
+	}{
+		{":26257", "localhost:26257"},
+		{"0.0.0.0:26257", "localhost:26257"},
+		{"[::]:26257", "localhost:26257"},
+		{"[::1]:26257", "[::1]:26257"},
+		{"127.0.0.1:26257", "127.0.0.1:26257"},
+		{"db.example.com:26257", "db.example.com:26257"},
+	} {
+		t.Run(tc.addr, func(t *testing.T) {
+			cfg.SQLAdvertiseAddr = tc.addr
+			s, err := cfg.PGURL("app")
+			require.NoError(t, err)
+			u, err := url.Parse(s)
+			require.NoError(t, err)
+			require.Equal(t, tc.expHost, u.Host)
+		})
+	}
+}
+
+func TestPGURLForDatabase(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	cfg.Insecure = true
+	cfg.SQLAdvertiseAddr = "db.example.com:26257"
+
+	s, err := cfg.PGURLForDatabase("app", "mydb")
+	require.NoError(t, err)
+	u, err := url.Parse(s)
+	require.NoError(t, err)
+	require.Equal(t, "app", u.User.Username())
+	require.Equal(t, "db.example.com:26257", u.Host)
+	require.Equal(t, "/mydb", u.Path)
+	require.Equal(t, "disable", u.Query().Get("sslmode"))
+
+	// An empty database produces the same URL as PGURL.
+	s, err = cfg.PGURLForDatabase("app", "")
+	require.NoError(t, err)
+	exp, err := cfg.PGURL("app")
+	require.NoError(t, err)
+	require.Equal(t, exp, s)
+}
+
+func TestPGURLForUnixSocket(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	cfg.Insecure = true
+	cfg.SQLAdvertiseAddr = "db.example.com:26258"
+
+	s, err := cfg.PGURLForUnixSocket("app", "/var/run/cockroach")
+	require.NoError(t, err)
+	require.Equal(t,
+		"postgresql://app@/defaultdb?host=%2Fvar%2Frun%2Fcockroach&port=26258&sslmode=disable", s)
+
+	// Secure servers use the same security options as over TCP.
+	cfg.Insecure = false
+	cfg.SSLCertsDir = t.TempDir()
+	s, err = cfg.PGURLForUnixSocket("app", "/var/run/cockroach")
+	require.NoError(t, err)
+	u, err := url.Parse(s)
+	require.NoError(t, err)
+	require.Empty(t, u.Host)
+	require.Equal(t, "/var/run/cockroach", u.Query().Get("host"))
+	tcp, err := cfg.PGURLStruct("app")
+	require.NoError(t, err)
+	require.Equal(t, tcp.Query().Get("sslmode"), u.Query().Get("sslmode"))
+}
+
+func TestPGURLOptions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	cfg.Insecure = true
+	cfg.SQLAdvertiseAddr = "db.example.com:26257"
+	base, err := cfg.PGURL("app")
+	require.NoError(t, err)
+
+	// The options are appended, in key order, after the parameters set by
+	// PGURL.
+	cfg.PGURLOptions = url.Values{
+		"connect_timeout":  {"10"},
+		"application_name": {"pool"},
+	}
+	s, err := cfg.PGURL("app")
+	require.NoError(t, err)
+	require.Equal(t, base+"&application_name=pool&connect_timeout=10", s)
+
+	// The security options cannot be overridden.
+	cfg.PGURLOptions = url.Values{"sslmode": {"require"}}
+	_, err = cfg.PGURL("app")
+	require.ErrorContains(t, err, "PG URL option sslmode cannot be overridden")
+
+	cfg.Insecure = false
+	cfg.SSLCertsDir = t.TempDir()
+	u, err := cfg.PGURLStruct("app")
+	require.NoError(t, err)
+	keys := []string{"sslcert", "sslkey", "sslrootcert"}
+	for key := range u.Query() {
+		keys = append(keys, key)
+	}
+	for _, key := range keys {
+		cfg.PGURLOptions = url.Values{key: {"clobbered"}}
+		_, err = cfg.PGURL("app")
+		require.ErrorContains(t, err, "cannot be overridden", key)
+	}
+}
+
+func TestPGSSLMode(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	cfg.SQLAdvertiseAddr = "db.example.com:26257"
+	cfg.SSLCertsDir = t.TempDir()
+	caCertPath := filepath.Join(cfg.SSLCertsDir, certnames.CACertFilename())
+	require.NoError(t, os.WriteFile(caCertPath, nil, 0644))
+
+	u, err := cfg.PGURLStruct("app")
+	require.NoError(t, err)
+	require.Equal(t, "verify-full", u.Query().Get("sslmode"))
+	require.Equal(t, caCertPath, u.Query().Get("sslrootcert"))
+
+	// The CA certificate is still used when the mode is overridden.
+	cfg.PGSSLMode = "verify-ca"
+	u, err = cfg.PGURLStruct("app")
+	require.NoError(t, err)
+	require.Equal(t, "verify-ca", u.Query().Get("sslmode"))
+	require.Equal(t, caCertPath, u.Query().Get("sslrootcert"))
+
+	cfg.PGSSLMode = "disable"
+	u, err = cfg.PGURLStruct("app")
+	require.NoError(t, err)
+	require.Equal(t, "disable", u.Query().Get("sslmode"))
+
+	cfg.PGSSLMode = "verify-host"
+	_, err = cfg.PGURLStruct("app")
+	require.ErrorContains(t, err, `invalid PG sslmode "verify-host"`)
+}
+
+func TestValidatePGSSLMode(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	require.NoError(t, cfg.validatePGSSLMode())
+	for _, mode := range []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"} {
+		cfg.PGSSLMode = mode
+		require.NoError(t, cfg.validatePGSSLMode(), mode)
+	}
+	for _, mode := range []string{"verify", "VERIFY-FULL", "none"} {
+		cfg.PGSSLMode = mode
+		require.Error(t, cfg.validatePGSSLMode(), mode)
+	}
+}
+
+func TestPGURLForTenant(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	cfg.Insecure = true
+	cfg.SQLAdvertiseAddr = "db.example.com:26257"
+
+	for _, tc := range []struct {
+		tenantID uint64
+		expName  string
+	}{
+		{1, "system"},
+		{10, "cluster-10"},
+	} {
+		s, err := cfg.PGURLForTenant("app", tc.tenantID)
+		require.NoError(t, err)
+		u, err := url.Parse(s)
+		require.NoError(t, err)
+		require.Equal(t, "app", u.User.Username())
+		require.Equal(t, "db.example.com:26257", u.Host)
+		require.Equal(t, "-ccluster="+tc.expName, u.Query().Get("options"))
+	}
+
+	_, err := cfg.PGURLForTenant("app", 0)
+	require.ErrorIs(t, err, roachpb.ErrInvalidTenantID)
+
+	// The URL is built like the other PG URLs.
+	cfg.SQLAdvertiseAddr = ":26257"
+	cfg.PGSSLMode = "require"
+	cfg.PGURLOptions = url.Values{"connect_timeout": {"10"}}
+	s, err := cfg.PGURLForTenant("app", 10)
+	require.NoError(t, err)
+	u, err := url.Parse(s)
+	require.NoError(t, err)
+	require.Equal(t, "localhost:26257", u.Host)
+	require.Equal(t, "require", u.Query().Get("sslmode"))
+	require.Equal(t, "10", u.Query().Get("connect_timeout"))
+	require.Equal(t, "-ccluster=cluster-10", u.Query().Get("options"))
+
+	// The tenant selection cannot be overridden.
+	cfg.PGURLOptions = url.Values{"options": {"-ccluster=other"}}
+	_, err = cfg.PGURLForTenant("app", 10)
+	require.ErrorContains(t, err, "PG URL option options cannot be overridden")
+}
+
+// TestPGURLForTenantName checks that PGURLForTenant selects tenants by the
+// name they are actually given when created without one.
+func TestPGURLForTenantName(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		DefaultTestTenant: base.TestControlsTenantsExplicitly,
+	})
+	defer s.Stopper().Stop(ctx)
+
+	_, err := db.Exec("SELECT crdb_internal.create_tenant(10)")
+	require.NoError(t, err)
+
+	cfg := s.SystemLayer().(*testServer).topLevelServer.cfg
+	for _, tenantID := range []uint64{1, 10} {
+		var name string
+		require.NoError(t, db.QueryRow(
+			"SELECT name FROM system.tenants WHERE id = $1", tenantID).Scan(&name))
+		pgURL, err := cfg.PGURLForTenant("root", tenantID)
+		require.NoError(t, err)
+		u, err := url.Parse(pgURL)
+		require.NoError(t, err)
+		require.Equal(t, "-ccluster="+name, u.Query().Get("options"), tenantID)
+	}
+}
+
+func TestWarnLinearizableMaxOffset(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	cfg := MakeConfig(ctx, cluster.MakeTestingClusterSettings())
+	cfg.MaxOffset = MaxOffsetType(time.Second)
+
+	// A large offset alone is not worth a warning.
+	require.False(t, cfg.warnLinearizableMaxOffset(ctx))
+
+	// Neither is linearizable mode with a small offset.
+	cfg.Linearizable = true
+	cfg.MaxOffset = MaxOffsetType(100 * time.Millisecond)
+	require.False(t, cfg.warnLinearizableMaxOffset(ctx))
+
+	cfg.MaxOffset = MaxOffsetType(time.Second)
+	require.True(t, cfg.warnLinearizableMaxOffset(ctx))
+
+	log.FlushFiles()
+	entries, err := log.FetchEntriesFromFiles(
+		0, /* startTimestamp */
+		math.MaxInt64,
+		10000, /* maxEntries */
+		regexp.MustCompile("linearizable mode is enabled with a max clock offset"),
+		log.WithMarkedSensitiveData)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Contains(t, entries[0].Message, "1s")
+}
+
+func TestWarnings(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	defer func(prev func(context.Context) (int64, error)) { totalMemory = prev }(totalMemory)
+	totalMemory = func(context.Context) (int64, error) { return 32 << 30, nil }
+
+	cfg := MakeConfig(ctx, cluster.MakeTestingClusterSettings())
+	require.Empty(t, cfg.Warnings())
+
+	cfg.Linearizable = true
+	cfg.MaxOffset = MaxOffsetType(time.Second)
+	cfg.warnLinearizableMaxOffset(ctx)
+	cfg.Stores.Specs = []base.StoreSpec{{InMemory: true, Size: base.SizeSpec{InBytes: 64 << 30}}}
+	require.NoError(t, cfg.validateInMemoryStoreSizes(ctx))
+
+	warnings := cfg.Warnings()
+	require.Len(t, warnings, 2)
+	require.Contains(t, warnings[0], "linearizable mode is enabled with a max clock offset of 1s")
+	require.Contains(t, warnings[1], "the 1 in-memory stores add up to 64 GiB")
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
+)
+
+// TopologyDOT renders the node and its configured stores, with their
+// attributes, locality tiers and sizes, as a Graphviz DOT graph. It only
+// reads the parsed configuration and may be called before the node starts.
+func (cfg *Config) TopologyDOT() string {
+	var b strings.Builder
+	b.WriteString("digraph topology {\n")
+	b.WriteString("  node [shape=box];\n")
+
+	nodeLabel := []string{"node"}
+	if cfg.AdvertiseAddr != "" {
+		nodeLabel = append(nodeLabel, "address: "+cfg.AdvertiseAddr)
+	}
+	if attrs := splitAttributes(cfg.Attrs); len(attrs) > 0 {
+		nodeLabel = append(nodeLabel, "attrs: "+strings.Join(attrs, ", "))
+	}
+	for _, tier := range cfg.Locality.Tiers {
+		nodeLabel = append(nodeLabel, fmt.Sprintf("%s: %s", tier.Key, tier.Value))
+	}
+	fmt.Fprintf(&b, "  %s [label=%s];\n", dotQuote("node"), dotQuote(nodeLabel...))
+
+	for i, spec := range cfg.Stores.Specs {
+		id := fmt.Sprintf("store%d", i)
+		storeLabel := []string{fmt.Sprintf("store %d", i)}
+		if spec.InMemory {
+			storeLabel = append(storeLabel, "in-memory")
+		} else {
+			storeLabel = append(storeLabel, "path: "+spec.Path)
+		}
+		if attrs := spec.Attributes.Attrs; len(attrs) > 0 {
+			storeLabel = append(storeLabel, "attrs: "+strings.Join(attrs, ", "))
+		}
+		storeLabel = append(storeLabel, "size: "+topologyStoreSize(spec.Size))
+		fmt.Fprintf(&b, "  %s [label=%s];\n", dotQuote(id), dotQuote(storeLabel...))
+		fmt.Fprintf(&b, "  %s -> %s;\n", dotQuote("node"), dotQuote(id))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func topologyStoreSize(size base.SizeSpec) string {
+	switch {
+	case size.InBytes != 0:
+		return string(humanizeutil.IBytes(size.InBytes))
+	case size.Percent != 0:
+		return strconv.FormatFloat(size.Percent, 'f', -1, 64) + "%"
+	default:
+		return "unbounded"
+	}
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// dotQuote returns a quoted DOT string made of the given lines.
+func dotQuote(lines ...string) string {
+	for i := range lines {
+		lines[i] = dotEscaper.Replace(lines[i])
+	}
+	return `"` + strings.Join(lines, `\n`) + `"`
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopologyDOT(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	cfg.AdvertiseAddr = "n1.example.com:26257"
+	cfg.Attrs = "gpu"
+	cfg.Locality = roachpb.Locality{Tiers: []roachpb.Tier{{Key: "region", Value: "us-east1"}}}
+	cfg.Stores = base.StoreSpecList{Specs: []base.StoreSpec{
+		{
+			Path:       "/mnt/ssd",
+			Attributes: roachpb.Attributes{Attrs: []string{"ssd", "fast"}},
+			Size:       base.SizeSpec{InBytes: 10 << 30},
+		},
+		{
+			Path:       `/mnt/"hdd"`,
+			Attributes: roachpb.Attributes{Attrs: []string{"hdd"}},
+			Size:       base.SizeSpec{Percent: 50},
+		},
+		{InMemory: true},
+	}}
+
+	require.Equal(t, `digraph topology {
+  node [shape=box];
+  "node" [label="node\naddress: n1.example.com:26257\nattrs: gpu\nregion: us-east1"];
+  "store0" [label="store 0\npath: /mnt/ssd\nattrs: ssd, fast\nsize: 10 GiB"];
+  "node" -> "store0";
+  "store1" [label="store 1\npath: /mnt/\"hdd\"\nattrs: hdd\nsize: 50%"];
+  "node" -> "store1";
+  "store2" [label="store 2\nin-memory\nsize: unbounded"];
+  "node" -> "store2";
+}
+`, cfg.TopologyDOT())
+}
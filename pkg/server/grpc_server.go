@@ -31,13 +31,17 @@ type grpcServer struct {
 	mode                   serveMode
 }
 
-func newGRPCServer(ctx context.Context, rpcCtx *rpc.Context) (*grpcServer, error) {
+func newGRPCServer(
+	ctx context.Context, rpcCtx *rpc.Context, streamWorkers int,
+) (*grpcServer, error) {
 	s := &grpcServer{}
 	s.mode.set(modeInitializing)
 	srv, interceptorInfo, err := rpc.NewServerEx(
-		ctx, rpcCtx, rpc.WithInterceptor(func(path string) error {
+		ctx, rpcCtx,
+		rpc.WithInterceptor(func(path string) error {
 			return s.intercept(path)
-		}))
+		}),
+		rpc.WithStreamWorkers(streamWorkers))
 	if err != nil {
 		return nil, err
 	}
@@ -13,12 +13,25 @@ package server
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvstorage"
 	"github.com/cockroachdb/cockroach/pkg/obs"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/lease"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/stats"
+	"github.com/cockroachdb/cockroach/pkg/util/admission"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
 	"github.com/cockroachdb/errors"
 )
 
@@ -48,11 +61,30 @@ func (s *topLevelServer) RunInitialSQL(
 		return nil
 	}
 
+	// The initial SQL does not run again on subsequent starts, so a step
+	// which fails would leave the cluster partially initialized for good.
+	// There is no transaction to make the steps atomic, as cluster settings
+	// cannot be set in one, so each step is idempotent and retried instead.
+	for _, step := range []struct {
+		desc string
+		fn   func(context.Context) error
+	}{
+		{"seed cluster settings", s.seedClusterSettings},
+		{"seed the default GC TTL", s.seedDefaultGCTTL},
+		{"seed the default time zone", s.seedDefaultTimeZone},
+		{"create the bootstrap backup schedule", s.seedBootstrapBackupSchedule},
+	} {
+		if err := retryInitialSQL(ctx, step.fn); err != nil {
+			log.Ops.Errorf(ctx, "could not %s: %v", step.desc, err)
+			return err
+		}
+	}
+
 	if startSingleNode {
 		// For start-single-node, set the default replication factor to
 		// 1 so as to avoid warning messages and unnecessary rebalance
 		// churn.
-		if err := s.disableReplication(ctx); err != nil {
+		if err := retryInitialSQL(ctx, s.disableReplication); err != nil {
 			log.Ops.Errorf(ctx, "could not disable replication: %v", err)
 			return err
 		}
@@ -61,7 +93,9 @@ func (s *topLevelServer) RunInitialSQL(
 	}
 
 	if adminUser != "" && !s.Insecure() {
-		if err := s.createAdminUser(ctx, adminUser, adminPassword); err != nil {
+		if err := retryInitialSQL(ctx, func(ctx context.Context) error {
+			return s.createAdminUser(ctx, adminUser, adminPassword)
+		}); err != nil {
 			return err
 		}
 	}
@@ -69,19 +103,138 @@ func (s *topLevelServer) RunInitialSQL(
 	return nil
 }
 
+// initialSQLRetryOptions bounds the attempts made at each step of the initial
+// SQL. It is overridden in tests.
+var initialSQLRetryOptions = retry.Options{
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	MaxRetries:     8,
+}
+
+// retryInitialSQL runs an idempotent step of the initial SQL until it
+// succeeds, retrying failures with initialSQLRetryOptions, and returns the
+// last error if it never does.
+func retryInitialSQL(ctx context.Context, step func(context.Context) error) (err error) {
+	for r := retry.StartWithCtx(ctx, initialSQLRetryOptions); r.Next(); {
+		if err = step(ctx); err == nil {
+			return nil
+		}
+		log.Ops.Warningf(ctx, "initial SQL step failed, retrying: %v", err)
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	return err
+}
+
 // RunInitialSQL implements cli.serverStartupInterface.
 func (s *SQLServerWrapper) RunInitialSQL(context.Context, bool, string, string) error {
 	return nil
 }
 
-// createAdminUser creates an admin user with the given name.
+// clusterSettingSeed is a cluster setting value to apply when the cluster is
+// initialized for the first time.
+type clusterSettingSeed struct {
+	name  settings.SettingName
+	value string
+}
+
+// initialClusterSettings returns the cluster settings which the configuration
+// requires to be seeded when the cluster is initialized.
+func (cfg *Config) initialClusterSettings() []clusterSettingSeed {
+	var seeds []clusterSettingSeed
+	if cfg.DefaultHashShardedBuckets > 0 {
+		seeds = append(seeds, clusterSettingSeed{
+			name:  tabledesc.DefaultPrimaryKeyHashShardBucketCount.Name(),
+			value: strconv.Itoa(cfg.DefaultHashShardedBuckets),
+		})
+	}
+	if cfg.DefaultMaxIntentsBytes > 0 {
+		seeds = append(seeds, clusterSettingSeed{
+			name:  kvcoord.TrackedWritesMaxSize.Name(),
+			value: strconv.FormatInt(cfg.DefaultMaxIntentsBytes, 10),
+		})
+	}
+	if v := cfg.DefaultAdmissionConfig.ElasticCPUMinUtilization; v > 0 {
+		seeds = append(seeds, clusterSettingSeed{
+			name:  admission.ElasticCPUMinUtilization.Name(),
+			value: strconv.FormatFloat(v, 'g', -1, 64),
+		})
+	}
+	if v := cfg.DefaultAdmissionConfig.ElasticCPUMaxUtilization; v > 0 {
+		seeds = append(seeds, clusterSettingSeed{
+			name:  admission.ElasticCPUMaxUtilization.Name(),
+			value: strconv.FormatFloat(v, 'g', -1, 64),
+		})
+	}
+	if cfg.MaxSessionResultBytes > 0 {
+		seeds = append(seeds, clusterSettingSeed{
+			name:  pgwire.ConnResultsBufferSize.Name(),
+			value: strconv.FormatInt(cfg.MaxSessionResultBytes, 10),
+		})
+	}
+	if cfg.DescriptorLeaseDuration > 0 {
+		seeds = append(seeds, clusterSettingSeed{
+			name:  lease.LeaseDuration.Name(),
+			value: cfg.DescriptorLeaseDuration.String(),
+		})
+	}
+	if cfg.DefaultDistSQLMode != "" {
+		seeds = append(seeds, clusterSettingSeed{
+			name:  sql.DistSQLClusterExecMode.Name(),
+			value: cfg.DefaultDistSQLMode,
+		})
+	}
+	if cfg.SlowQueryLogThreshold > 0 {
+		seeds = append(seeds, clusterSettingSeed{
+			name:  sql.SlowQueryLogThreshold.Name(),
+			value: cfg.SlowQueryLogThreshold.String(),
+		})
+	}
+	if cfg.AutoStatsMinStaleRows > 0 {
+		seeds = append(seeds, clusterSettingSeed{
+			name:  stats.AutomaticStatisticsMinStaleRows.Name(),
+			value: strconv.FormatInt(cfg.AutoStatsMinStaleRows, 10),
+		})
+	}
+	return seeds
+}
+
+// BootstrapSettings returns the values, by cluster setting name, which the
+// configuration seeds when the cluster is initialized for the first time.
+func (cfg *Config) BootstrapSettings() map[string]string {
+	seeds := cfg.initialClusterSettings()
+	values := make(map[string]string, len(seeds))
+	for _, seed := range seeds {
+		values[string(seed.name)] = seed.value
+	}
+	return values
+}
+
+// seedClusterSettings applies the settings returned by initialClusterSettings.
+func (s *topLevelServer) seedClusterSettings(ctx context.Context) error {
+	ie := s.sqlServer.internalExecutor
+	for _, seed := range s.cfg.initialClusterSettings() {
+		if _, err := ie.Exec(
+			ctx, "seed-cluster-setting", nil, /* txn */
+			fmt.Sprintf("SET CLUSTER SETTING %s = %s", seed.name, lexbase.EscapeSQLString(seed.value)),
+		); err != nil {
+			return errors.Wrapf(err, "seeding cluster setting %s", seed.name)
+		}
+		log.Ops.Infof(ctx, "cluster setting %s initialized to %s", seed.name, seed.value)
+	}
+	return nil
+}
+
+// createAdminUser creates an admin user with the given name. It can be
+// retried if the user was created but not granted the admin role.
 func (s *topLevelServer) createAdminUser(
 	ctx context.Context, adminUser, adminPassword string,
 ) error {
 	ie := s.sqlServer.internalExecutor
 	_, err := ie.Exec(
 		ctx, "admin-user", nil,
-		fmt.Sprintf("CREATE USER %s WITH PASSWORD $1", adminUser),
+		fmt.Sprintf("CREATE USER IF NOT EXISTS %s WITH PASSWORD $1", adminUser),
 		adminPassword,
 	)
 	if err != nil {
@@ -92,6 +245,71 @@ func (s *topLevelServer) createAdminUser(
 	return err
 }
 
+// seedDefaultGCTTL applies DefaultGCTTL to the default zone configuration.
+func (s *topLevelServer) seedDefaultGCTTL(ctx context.Context) error {
+	ttl := s.cfg.DefaultGCTTL
+	if ttl <= 0 {
+		return nil
+	}
+	if _, err := s.sqlServer.internalExecutor.Exec(ctx, "seed-gc-ttl", nil, /* txn */
+		fmt.Sprintf("ALTER RANGE default CONFIGURE ZONE USING gc.ttlseconds = %d", int64(ttl/time.Second)),
+	); err != nil {
+		return errors.Wrap(err, "seeding default GC TTL")
+	}
+	log.Ops.Infof(ctx, "default GC TTL initialized to %s", ttl)
+	return nil
+}
+
+// seedDefaultTimeZone makes the configured DefaultTimeZone, if any, the
+// default time zone of new sessions of all roles.
+func (s *topLevelServer) seedDefaultTimeZone(ctx context.Context) error {
+	tz := s.cfg.DefaultTimeZone
+	if tz == "" {
+		return nil
+	}
+	if _, err := s.sqlServer.internalExecutor.Exec(ctx, "seed-time-zone", nil, /* txn */
+		"ALTER ROLE ALL SET timezone = "+lexbase.EscapeSQLString(tz),
+	); err != nil {
+		return errors.Wrap(err, "seeding default time zone")
+	}
+	log.Ops.Infof(ctx, "default time zone initialized to %s", tz)
+	return nil
+}
+
+// bootstrapBackupScheduleLabel is the label of the schedule created for
+// BootstrapBackupSchedule.
+const bootstrapBackupScheduleLabel = "bootstrap-backup"
+
+// bootstrapBackupScheduleStmt returns the statement creating the backup
+// schedule configured by BootstrapBackupSchedule. The schedule is only
+// created if there is none with its label yet, so that the statement can be
+// retried.
+func bootstrapBackupScheduleStmt(schedule, uri string) string {
+	return fmt.Sprintf("CREATE SCHEDULE IF NOT EXISTS %s FOR BACKUP INTO %s RECURRING %s",
+		lexbase.EscapeSQLString(bootstrapBackupScheduleLabel),
+		lexbase.EscapeSQLString(uri), lexbase.EscapeSQLString(schedule))
+}
+
+// seedBootstrapBackupSchedule creates the backup schedule configured by
+// BootstrapBackupSchedule, if any.
+func (s *topLevelServer) seedBootstrapBackupSchedule(ctx context.Context) error {
+	schedule, uri := s.cfg.BootstrapBackupSchedule, s.cfg.BootstrapBackupURI
+	if schedule == "" {
+		return nil
+	}
+	if _, err := s.sqlServer.internalExecutor.Exec(ctx, "seed-backup-schedule", nil, /* txn */
+		bootstrapBackupScheduleStmt(schedule, uri),
+	); err != nil {
+		return errors.Wrap(err, "creating bootstrap backup schedule")
+	}
+	sanitized, err := cloud.SanitizeExternalStorageURI(uri, nil /* extraParams */)
+	if err != nil {
+		sanitized = "<unparseable URI>"
+	}
+	log.Ops.Infof(ctx, "backup schedule %s into %s created", schedule, sanitized)
+	return nil
+}
+
 // disableReplication changes the replication factor on
 // all defined zones to become 1. This is used by start-single-node
 // and demo to define single-node clusters, so as to avoid
@@ -0,0 +1,171 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// TestInitialSQLSeeds verifies that the options seeded when the cluster is
+// initialized are applied by the initial SQL, that nothing is seeded by
+// default, and that the seeds are not applied again on subsequent starts.
+func TestInitialSQLSeeds(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		DefaultTestTenant: base.TestIsSpecificToStorageLayerAndNeedsASystemTenant,
+	})
+	defer s.Stopper().Stop(ctx)
+	ts := s.SystemLayer().(*testServer).topLevelServer
+	runInitialSQL := func() {
+		require.NoError(t, ts.RunInitialSQL(ctx, false /* startSingleNode */, "" /* adminUser */, "" /* adminPassword */))
+	}
+
+	// Nothing is seeded by default.
+	runInitialSQL()
+	sqlutils.MakeSQLRunner(db).CheckQueryResults(t,
+		`SELECT count(*) FROM system.settings WHERE name = 'sql.schema.default_primary_key_hash_shard_bucket_count'`,
+		[][]string{{"0"}})
+
+	setKnobs := func(scale int) {
+		ts.cfg.DefaultHashShardedBuckets = 32 * scale
+		ts.cfg.DefaultMaxIntentsBytes = int64(8<<20) * int64(scale)
+		ts.cfg.DefaultAdmissionConfig = AdmissionConfig{
+			ElasticCPUMinUtilization: 0.1 * float64(scale),
+			ElasticCPUMaxUtilization: 0.4 * float64(scale),
+		}
+		ts.cfg.MaxSessionResultBytes = int64(32<<10) * int64(scale)
+		ts.cfg.DescriptorLeaseDuration = time.Duration(scale) * 10 * time.Minute
+		ts.cfg.SlowQueryLogThreshold = time.Duration(scale) * 250 * time.Millisecond
+		ts.cfg.AutoStatsMinStaleRows = int64(1000 * scale)
+		ts.cfg.DefaultGCTTL = time.Duration(scale) * 2 * time.Hour
+		ts.cfg.DefaultDistSQLMode = "off"
+		ts.cfg.DefaultTimeZone = "America/New_York"
+		if scale > 1 {
+			ts.cfg.DefaultDistSQLMode = "always"
+			ts.cfg.DefaultTimeZone = "Europe/Paris"
+		}
+	}
+	testCases := []struct {
+		option, query, expected string
+	}{
+		{"DefaultHashShardedBuckets",
+			`SHOW CLUSTER SETTING sql.schema.default_primary_key_hash_shard_bucket_count`, "32"},
+		{"DefaultMaxIntentsBytes", `SHOW CLUSTER SETTING kv.transaction.max_intents_bytes`, "8388608"},
+		{"DefaultAdmissionConfig", `SHOW CLUSTER SETTING admission.elastic_cpu.min_utilization`, "0.1"},
+		{"DefaultAdmissionConfig", `SHOW CLUSTER SETTING admission.elastic_cpu.max_utilization`, "0.4"},
+		{"MaxSessionResultBytes", `SHOW CLUSTER SETTING sql.defaults.results_buffer.size`, "32 KiB"},
+		{"MaxSessionResultBytes", `SHOW results_buffer_size`, "32768"},
+		{"DescriptorLeaseDuration", `SHOW CLUSTER SETTING sql.catalog.descriptor_lease_duration`, "00:10:00"},
+		{"DefaultDistSQLMode", `SHOW CLUSTER SETTING sql.defaults.distsql`, "off"},
+		{"SlowQueryLogThreshold", `SHOW CLUSTER SETTING sql.log.slow_query.latency_threshold`, "00:00:00.25"},
+		{"AutoStatsMinStaleRows", `SHOW CLUSTER SETTING sql.stats.automatic_collection.min_stale_rows`, "1000"},
+		{"DefaultGCTTL", `SELECT raw_config_sql LIKE '%gc.ttlseconds = 7200%'
+			FROM [SHOW ZONE CONFIGURATION FROM RANGE default]`, "true"},
+		{"DefaultTimeZone", `SHOW timezone`, "America/New_York"},
+	}
+	check := func(t *testing.T) {
+		// The session defaults only apply to new sessions.
+		sqlDB := sqlutils.MakeSQLRunner(s.SQLConn(t))
+		for _, tc := range testCases {
+			t.Run(tc.option, func(t *testing.T) {
+				sqlDB.CheckQueryResults(t, tc.query, [][]string{{tc.expected}})
+			})
+		}
+	}
+
+	setKnobs(1)
+	runInitialSQL()
+	t.Run("bootstrap", check)
+
+	// Emulate a restart: the initial SQL does not run again.
+	ts.node.initialStart = false
+	setKnobs(2)
+	runInitialSQL()
+	t.Run("restart", check)
+}
+
+// TestBootstrapBackupScheduleStmt checks the statement creating the bootstrap
+// backup schedule. The schedule itself cannot be created by this test, which
+// does not link the CCL code implementing backups.
+func TestBootstrapBackupScheduleStmt(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	stmt := bootstrapBackupScheduleStmt("@daily", "nodelocal://1/it's")
+	require.Equal(t, `CREATE SCHEDULE IF NOT EXISTS 'bootstrap-backup' `+
+		`FOR BACKUP INTO e'nodelocal://1/it\'s' RECURRING '@daily'`, stmt)
+	_, err := parser.ParseOne(stmt)
+	require.NoError(t, err)
+}
+
+func TestRetryInitialSQL(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	defer func(opts retry.Options) { initialSQLRetryOptions = opts }(initialSQLRetryOptions)
+	initialSQLRetryOptions = retry.Options{
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		MaxRetries:     3,
+	}
+	ctx := context.Background()
+
+	// A step failing transiently is retried until it succeeds.
+	var attempts int
+	require.NoError(t, retryInitialSQL(ctx, func(context.Context) error {
+		if attempts++; attempts < 3 {
+			return errors.New("transient")
+		}
+		return nil
+	}))
+	require.Equal(t, 3, attempts)
+
+	// Once the retries are exhausted, the last error is returned.
+	attempts = 0
+	require.EqualError(t, retryInitialSQL(ctx, func(context.Context) error {
+		attempts++
+		return errors.Newf("attempt %d", attempts)
+	}), "attempt 4")
+	require.Equal(t, 4, attempts)
+}
+
+func TestBootstrapSettings(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	require.Empty(t, cfg.BootstrapSettings())
+
+	cfg.DefaultHashShardedBuckets = 8
+	cfg.DefaultDistSQLMode = "off"
+	cfg.SlowQueryLogThreshold = 250 * time.Millisecond
+	require.Equal(t, map[string]string{
+		"sql.schema.default_primary_key_hash_shard_bucket_count": "8",
+		"sql.defaults.distsql":                 "off",
+		"sql.log.slow_query.latency_threshold": "250ms",
+	}, cfg.BootstrapSettings())
+}
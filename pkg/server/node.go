@@ -1073,6 +1073,9 @@ func (n *Node) UpdateIOThreshold(id roachpb.StoreID, threshold *admissionpb.IOTh
 type diskStatsMap struct {
 	provisionedRate map[roachpb.StoreID]base.ProvisionedRateSpec
 	diskMonitors    map[roachpb.StoreID]kvserver.DiskStatsMonitor
+	// ioTokenRate holds the admission IO token rate of the stores which
+	// configure one.
+	ioTokenRate map[roachpb.StoreID]int64
 }
 
 func (dsm *diskStatsMap) tryPopulateAdmissionDiskStats(
@@ -1117,6 +1120,7 @@ func (dsm *diskStatsMap) initDiskStatsMap(
 	*dsm = diskStatsMap{
 		provisionedRate: make(map[roachpb.StoreID]base.ProvisionedRateSpec),
 		diskMonitors:    make(map[roachpb.StoreID]kvserver.DiskStatsMonitor),
+		ioTokenRate:     make(map[roachpb.StoreID]int64),
 	}
 	for i := range engines {
 		if specs[i].Path == "" || specs[i].InMemory {
@@ -1132,6 +1136,9 @@ func (dsm *diskStatsMap) initDiskStatsMap(
 		}
 		dsm.provisionedRate[id.StoreID] = specs[i].ProvisionedRateSpec
 		dsm.diskMonitors[id.StoreID] = monitor
+		if rate := specs[i].AdmissionIOTokenRate; rate > 0 {
+			dsm.ioTokenRate[id.StoreID] = rate
+		}
 	}
 	return nil
 }
@@ -1161,6 +1168,28 @@ func (n *Node) registerEnginesForDiskStatsMap(
 	return nil
 }
 
+// enableWriteThroughputSampling enables the sampling of the write throughput
+// of the stores whose spec requests it.
+func (n *Node) enableWriteThroughputSampling(
+	ctx context.Context, specs []base.StoreSpec, engines []storage.Engine,
+) error {
+	for i := range engines {
+		if !specs[i].SampleThroughput {
+			continue
+		}
+		id, err := kvstorage.ReadStoreIdent(ctx, engines[i])
+		if err != nil {
+			return err
+		}
+		store, err := n.stores.GetStore(id.StoreID)
+		if err != nil {
+			return err
+		}
+		store.EnableWriteThroughputSampling()
+	}
+	return nil
+}
+
 // GetPebbleMetrics implements admission.PebbleMetricsProvider.
 func (n *Node) GetPebbleMetrics() []admission.StoreMetrics {
 	clusterProvisionedBandwidth := kvadmission.ProvisionedBandwidth.Get(
@@ -1181,7 +1210,9 @@ func (n *Node) GetPebbleMetrics() []admission.StoreMetrics {
 			StoreID:         store.StoreID(),
 			Metrics:         m.Metrics,
 			WriteStallCount: m.WriteStallCount,
-			DiskStats:       diskStats})
+			DiskStats:       diskStats,
+			IOTokenRate:     n.diskStatsMap.ioTokenRate[store.StoreID()],
+		})
 		return nil
 	})
 	return metrics
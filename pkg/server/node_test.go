@@ -1011,7 +1011,8 @@ func TestDiskStatsMap(t *testing.T) {
 			ProvisionedRateSpec: base.ProvisionedRateSpec{
 				ProvisionedBandwidth: 200,
 			},
-			Path: "bar",
+			Path:                 "bar",
+			AdmissionIOTokenRate: 64 << 20,
 		},
 	}
 	// Engines.
@@ -1044,6 +1045,8 @@ func TestDiskStatsMap(t *testing.T) {
 	}
 	// diskStatsMap initialized with these two stores.
 	require.NoError(t, dsm.initDiskStatsMap(specs, engines, diskManager))
+	// Only "bar" configures an IO token rate.
+	require.Equal(t, map[roachpb.StoreID]int64{5: 64 << 20}, dsm.ioTokenRate)
 
 	// Populate disk monitor stats.
 	diskManager.injectStats(map[string]disk.Stats{
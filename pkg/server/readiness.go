@@ -0,0 +1,54 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+)
+
+// ReadinessState is the runtime state of a node consulted by readiness checks.
+type ReadinessState interface {
+	// NumStores returns the number of stores the node has initialized.
+	NumStores() int
+}
+
+// ReadinessCheck is a named condition which must hold for the node to report
+// itself as ready in health checks.
+type ReadinessCheck struct {
+	Name  string
+	Check func(ctx context.Context, state ReadinessState) error
+}
+
+// ReadinessChecks returns the readiness checks implied by the configuration.
+// They are run by the health endpoint on top of the checks every node is
+// subject to, such as being live and accepting SQL clients.
+func (cfg *Config) ReadinessChecks() []ReadinessCheck {
+	var checks []ReadinessCheck
+	if minStores := cfg.MinStoresToStart; minStores > 0 {
+		checks = append(checks, ReadinessCheck{
+			Name: "min-stores",
+			Check: func(_ context.Context, state ReadinessState) error {
+				if n := state.NumStores(); n < minStores {
+					return errors.Errorf("%d of %d required stores initialized", n, minStores)
+				}
+				return nil
+			},
+		})
+	}
+	return checks
+}
+
+// NumStores implements the ReadinessState interface.
+func (s *topLevelServer) NumStores() int {
+	return s.node.stores.GetStoreCount()
+}
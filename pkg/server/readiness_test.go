@@ -0,0 +1,50 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeReadinessState struct {
+	numStores int
+}
+
+func (s fakeReadinessState) NumStores() int { return s.numStores }
+
+func TestReadinessChecks(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	cfg := MakeConfig(ctx, cluster.MakeTestingClusterSettings())
+	require.Empty(t, cfg.ReadinessChecks())
+
+	cfg.Stores.Specs = []base.StoreSpec{{InMemory: true}, {InMemory: true}}
+	cfg.MinStoresToStart = 2
+	require.NoError(t, cfg.validateMinStoresToStart())
+	checks := cfg.ReadinessChecks()
+	require.Len(t, checks, 1)
+	require.Equal(t, "min-stores", checks[0].Name)
+	require.ErrorContains(t, checks[0].Check(ctx, fakeReadinessState{numStores: 1}),
+		"1 of 2 required stores initialized")
+	require.NoError(t, checks[0].Check(ctx, fakeReadinessState{numStores: 2}))
+
+	cfg.MinStoresToStart = 3
+	require.Error(t, cfg.validateMinStoresToStart())
+}
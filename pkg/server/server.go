@@ -321,6 +321,9 @@ func NewServer(cfg Config, stopper *stop.Stopper) (serverctl.ServerStartupInterf
 		nodeRegistry,
 		cfg.Locality,
 	)
+	if cfg.ExpectedClusterSize > 0 {
+		g.SetExpectedClusterSize(cfg.ExpectedClusterSize)
+	}
 
 	tenantCapabilitiesTestingKnobs, _ := cfg.TestingKnobs.TenantCapabilitiesTestingKnobs.(*tenantcapabilities.TestingKnobs)
 	authorizer := tenantcapabilitiesauthorizer.New(cfg.Settings, tenantCapabilitiesTestingKnobs)
@@ -396,7 +399,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (serverctl.ServerStartupInterf
 	// and after ValidateAddrs().
 	rpcContext.CheckCertificateAddrs(ctx)
 
-	grpcServer, err := newGRPCServer(ctx, rpcContext)
+	grpcServer, err := newGRPCServer(ctx, rpcContext, cfg.RPCServerConcurrency)
 	if err != nil {
 		return nil, err
 	}
@@ -573,6 +576,7 @@ func NewServer(cfg Config, stopper *stop.Stopper) (serverctl.ServerStartupInterf
 		nodeLivenessFn,
 		/* deterministic */ false,
 	)
+	storePool.SetJoinRebalanceRate(cfg.JoinRebalanceRate)
 
 	storesForFlowControl := kvserver.MakeStoresForFlowControl(stores)
 	kvflowTokenDispatch := kvflowdispatch.New(nodeRegistry, storesForFlowControl, nodeIDContainer)
@@ -845,6 +849,9 @@ func NewServer(cfg Config, stopper *stop.Stopper) (serverctl.ServerStartupInterf
 		ScanInterval:                 cfg.ScanInterval,
 		ScanMinIdleTime:              cfg.ScanMinIdleTime,
 		ScanMaxIdleTime:              cfg.ScanMaxIdleTime,
+		PrioritizeUnderReplicated:    cfg.PrioritizeUnderReplicated,
+		PreferLocalLeases:            cfg.PreferLocalLeases,
+		QueueConcurrency:             cfg.QueueConcurrency,
 		HistogramWindowInterval:      cfg.HistogramWindowInterval(),
 		StorePool:                    storePool,
 		LogRangeAndNodeEvents:        cfg.EventLogEnabled,
@@ -1362,6 +1369,25 @@ func (s *topLevelServer) StorageClusterID() uuid.UUID {
 	return s.rpcContext.StorageClusterID.Get()
 }
 
+// gossipPeerLocalities returns the localities of the other nodes whose
+// descriptors are known through gossip.
+func (s *topLevelServer) gossipPeerLocalities() []roachpb.Locality {
+	var localities []roachpb.Locality
+	_ = s.gossip.IterateInfos(gossip.KeyNodeDescPrefix, func(_ string, info gossip.Info) error {
+		var desc roachpb.NodeDescriptor
+		if err := info.Value.GetProto(&desc); err != nil {
+			// The localities are only used for advisory warnings; skip the
+			// descriptors which cannot be decoded.
+			return nil //nolint:returnerrcheck
+		}
+		if desc.NodeID != s.NodeID() {
+			localities = append(localities, desc.Locality)
+		}
+		return nil
+	})
+	return localities
+}
+
 // NodeID returns the ID of this node within its cluster.
 func (s *topLevelServer) NodeID() roachpb.NodeID {
 	return s.node.Descriptor.NodeID
@@ -1784,11 +1810,15 @@ func (s *topLevelServer) PreStart(ctx context.Context) error {
 	// cluster. Someone has to gossip the ClusterID before Gossip is connected,
 	// but this gossip only happens once the first range has a leaseholder, i.e.
 	// when a quorum of nodes has gone fully operational.
+	s.cfg.peerLocalities = s.gossipPeerLocalities
 	_ = s.stopper.RunAsyncTask(workersCtx, "connect-gossip", func(ctx context.Context) {
 		log.Ops.Infof(ctx, "connecting to gossip network to verify cluster ID %q", state.clusterID)
 		select {
 		case <-s.gossip.Connected:
 			log.Ops.Infof(ctx, "node connected via gossip")
+			for _, warning := range s.cfg.ValidateJoinDiversity() {
+				log.Ops.Warningf(ctx, "%s", warning)
+			}
 		case <-ctx.Done():
 		case <-s.stopper.ShouldQuiesce():
 		}
@@ -1961,11 +1991,15 @@ func (s *topLevelServer) PreStart(ctx context.Context) error {
 	// wholly initialized stores (it reads the StoreIdentKeys). It also needs
 	// to come before the call into SetPebbleMetricsProvider, which internally
 	// uses the disk stats map we're initializing.
-	if err := s.node.registerEnginesForDiskStatsMap(s.cfg.Stores.Specs, s.engines, (*diskMonitorManager)(s.cfg.DiskMonitorManager)); err != nil {
+	if err := s.node.registerEnginesForDiskStatsMap(s.cfg.admissionStoreSpecs(), s.engines, (*diskMonitorManager)(s.cfg.DiskMonitorManager)); err != nil {
 		return errors.Wrapf(err, "failed to register engines for the disk stats map")
 	}
 	s.stopper.AddCloser(stop.CloserFn(func() { s.node.diskStatsMap.closeDiskMonitors() }))
 
+	if err := s.node.enableWriteThroughputSampling(ctx, s.cfg.Stores.Specs, s.engines); err != nil {
+		return errors.Wrapf(err, "failed to enable write throughput sampling")
+	}
+
 	// Stores have been initialized, so Node can now provide Pebble metrics.
 	//
 	// Note that all existing stores will be operational before Pebble-level
@@ -2310,6 +2344,8 @@ func (s *topLevelServer) AcceptClients(ctx context.Context) error {
 		s.pgL,
 		s.ClusterSettings(),
 		&s.cfg.SocketFile,
+		s.cfg.MaxConnsPerIP,
+		s.cfg.MaxNewConnsPerSecond,
 	); err != nil {
 		return err
 	}
@@ -16,6 +16,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/NYTimes/gziphandler"
 	"github.com/cockroachdb/cmux"
@@ -209,8 +210,9 @@ func (s *httpServer) setupRoutes(
 	}
 
 	// Admin/Status servers. These are used by the UI via RPC-over-HTTP.
-	s.mux.Handle(apiconstants.StatusPrefix, authenticatedHandler)
-	s.mux.Handle(apiconstants.AdminPrefix, authenticatedHandler)
+	apiHandler := withRequestTimeout(s.cfg.HTTPRequestTimeout, authenticatedHandler)
+	s.mux.Handle(apiconstants.StatusPrefix, apiHandler)
+	s.mux.Handle(apiconstants.AdminPrefix, apiHandler)
 
 	// The timeseries endpoint, used to produce graphs.
 	s.mux.Handle(ts.URLPrefix, authenticatedHandler)
@@ -251,6 +253,19 @@ func (s *httpServer) setupRoutes(
 	return nil
 }
 
+// withRequestTimeout bounds the context of the requests served by handler to
+// the given timeout. A zero timeout leaves the requests unbounded.
+func withRequestTimeout(timeout time.Duration, handler http.Handler) http.Handler {
+	if timeout == 0 {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithTimeout(req.Context(), timeout)
+		defer cancel()
+		handler.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
 func makeAdminAuthzCheckHandler(
 	adminAuthzCheck privchecker.CheckerForRPCHandlers, handler http.Handler,
 ) http.Handler {
@@ -14,14 +14,19 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/server/apiconstants"
 	"github.com/cockroachdb/cockroach/pkg/server/authserver"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -183,3 +188,30 @@ func TestVirtualClustersMultiTenant(t *testing.T) {
 	require.Equal(t, "application/json", resp.Header.Get("content-type"))
 	require.Equal(t, `{"virtual_clusters":["system","app"]}`, string(read))
 }
+
+func TestHTTPRequestTimeout(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	cfg := MakeConfig(context.Background(), cluster.MakeTestingClusterSettings())
+	require.Equal(t, defaultHTTPRequestTimeout, cfg.HTTPRequestTimeout)
+	require.NoError(t, cfg.validateHTTPRequestTimeout())
+	cfg.HTTPRequestTimeout = -time.Second
+	require.Error(t, cfg.validateHTTPRequestTimeout())
+
+	for _, timeout := range []time.Duration{0, time.Minute} {
+		var deadline time.Time
+		var hasDeadline bool
+		handler := withRequestTimeout(timeout, http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+			deadline, hasDeadline = req.Context().Deadline()
+		}))
+		start := timeutil.Now()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", apiconstants.AdminPrefix+"ranges", nil))
+		if timeout == 0 {
+			require.False(t, hasDeadline, "zero timeout must not bound requests")
+		} else {
+			require.True(t, hasDeadline)
+			require.WithinDuration(t, start.Add(timeout), deadline, 10*time.Second)
+		}
+	}
+}
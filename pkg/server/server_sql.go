@@ -543,11 +543,8 @@ func newSQLServer(ctx context.Context, cfg sqlServerArgs) (*SQLServer, error) {
 	}
 
 	var jobAdoptionStopFile string
-	for _, spec := range cfg.Stores.Specs {
-		if !spec.InMemory && spec.Path != "" {
-			jobAdoptionStopFile = filepath.Join(spec.Path, jobs.PreventAdoptionFile)
-			break
-		}
+	if i, ok := cfg.FirstDiskStoreIndex(); ok {
+		jobAdoptionStopFile = filepath.Join(cfg.Stores.Specs[i].Path, jobs.PreventAdoptionFile)
 	}
 
 	if err := cfg.stopper.RunAsyncTask(ctx, "tracer-snapshots", func(context.Context) {
@@ -1856,6 +1853,8 @@ func startServeSQL(
 	pgL net.Listener,
 	st *cluster.Settings,
 	socketFileCfg *string,
+	maxConnsPerIP int,
+	maxNewConnsPerSecond int,
 ) error {
 	log.Ops.Info(ctx, "serving sql connections")
 	// Start servicing SQL connections.
@@ -1866,10 +1865,15 @@ func startServeSQL(
 	// objects when the stopper tells us to shut down.
 	connManager := netutil.MakeTCPServer(ctx, stopper)
 
+	// Reject connections from clients which already hold too many, and
+	// throttle the rate at which the remaining ones are accepted.
+	tcpL := netutil.LimitConnRate(ctx, stopper,
+		netutil.LimitConnsPerIP(ctx, pgL, maxConnsPerIP), maxNewConnsPerSecond)
+
 	_ = stopper.RunAsyncTaskEx(ctx,
 		stop.TaskOpts{TaskName: "pgwire-listener", SpanOpt: stop.SterileRootSpan},
 		func(ctx context.Context) {
-			err := connManager.ServeWith(ctx, pgL, func(ctx context.Context, conn net.Conn) {
+			err := connManager.ServeWith(ctx, tcpL, func(ctx context.Context, conn net.Conn) {
 				connCtx := pgPreServer.AnnotateCtxForIncomingConn(ctx, conn)
 				tcpKeepAlive.configure(connCtx, conn)
 
@@ -56,7 +56,7 @@ func makeTCPKeepAliveManager(settings *cluster.Settings) tcpKeepAliveManager {
 // configure attempts to set TCP keep-alive on
 // connection. Does not fail on errors.
 func (k *tcpKeepAliveManager) configure(ctx context.Context, conn net.Conn) {
-	muxConn, ok := conn.(*cmux.MuxConn)
+	muxConn, ok := muxConnOf(conn)
 	if !ok {
 		return
 	}
@@ -96,3 +96,19 @@ func (k *tcpKeepAliveManager) configure(ctx context.Context, conn net.Conn) {
 		log.VEventf(ctx, 2, "setting TCP keep-alive interval %d and probe count to %d for pgwire", probeFrequency, probeCount)
 	}
 }
+
+// muxConnOf returns the cmux connection underlying conn. It looks through the
+// wrappers, such as the one of netutil.LimitConnsPerIP, which expose the
+// connection they wrap with a NetConn method.
+func muxConnOf(conn net.Conn) (*cmux.MuxConn, bool) {
+	for {
+		if muxConn, ok := conn.(*cmux.MuxConn); ok {
+			return muxConn, true
+		}
+		wrapper, ok := conn.(interface{ NetConn() net.Conn })
+		if !ok {
+			return nil, false
+		}
+		conn = wrapper.NetConn()
+	}
+}
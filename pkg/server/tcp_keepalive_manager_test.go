@@ -20,6 +20,7 @@ import (
 
 	"github.com/cockroachdb/cmux"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
@@ -31,6 +32,13 @@ import (
 func TestKeepAliveManager(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
+
+	// The connections accepted under a per-IP connection limit are wrapped,
+	// which must not prevent keep-alive from being configured.
+	testutils.RunTrueAndFalse(t, "limit-conns-per-ip", testKeepAliveManager)
+}
+
+func testKeepAliveManager(t *testing.T, limitConnsPerIP bool) {
 	ctx := context.Background()
 
 	grp := ctxgroup.WithContext(ctx)
@@ -47,6 +55,9 @@ func TestKeepAliveManager(t *testing.T) {
 	})
 
 	listener := mux.Match(cmux.Any())
+	if limitConnsPerIP {
+		listener = netutil.LimitConnsPerIP(ctx, listener, 1 /* maxConns */)
+	}
 	grp.Go(func() error {
 		netutil.FatalIfUnexpected(mux.Serve())
 		return nil
@@ -72,14 +83,10 @@ func TestKeepAliveManager(t *testing.T) {
 	require.NoError(t, err)
 	// Confirm the settings are set on any TCP connection that we
 	// process.
-	muxConn, ok := conn.(*cmux.MuxConn)
-	if !ok {
-		return
-	}
+	muxConn, ok := muxConnOf(conn)
+	require.True(t, ok)
 	tcpConn, ok := muxConn.Conn.(*net.TCPConn)
-	if !ok {
-		return
-	}
+	require.True(t, ok)
 	idleTime, probeInterval, probeCount, err := sysutil.GetKeepAliveSettings(tcpConn)
 	require.NoError(t, err)
 
@@ -970,6 +970,8 @@ func (s *SQLServerWrapper) AcceptClients(ctx context.Context) error {
 			s.pgL,
 			s.ClusterSettings(),
 			&s.sqlServer.cfg.SocketFile,
+			s.sqlServer.cfg.MaxConnsPerIP,
+			s.sqlServer.cfg.MaxNewConnsPerSecond,
 		); err != nil {
 			return err
 		}
@@ -1290,7 +1292,7 @@ func makeTenantSQLServerArgs(
 	externalStorage := esb.makeExternalStorage
 	externalStorageFromURI := esb.makeExternalStorageFromURI
 
-	grpcServer, err := newGRPCServer(startupCtx, rpcContext)
+	grpcServer, err := newGRPCServer(startupCtx, rpcContext, baseCfg.RPCServerConcurrency)
 	if err != nil {
 		return sqlServerArgs{}, err
 	}
@@ -105,6 +105,9 @@ type TestingKnobs struct {
 	// When supplied to a TestCluster, StickyVFSIDs will be associated auto-
 	// matically to the StoreSpecs used.
 	StickyVFSRegistry fs.StickyRegistry
+	// StoreFailureInjection makes CreateEngines wrap the filesystems of the
+	// in-memory stores so that Config.SimulateStoreFailure can fail them.
+	StoreFailureInjection bool
 	// WallClock is used to inject a custom clock for testing the server. It is
 	// typically either an hlc.HybridManualClock or hlc.ManualClock.
 	WallClock hlc.WallClock
@@ -317,6 +317,18 @@ var DefaultHashShardedIndexBucketCount = settings.RegisterIntSetting(
 	settings.NonNegativeInt,
 	settings.WithPublic)
 
+// DefaultPrimaryKeyHashShardBucketCount is the cluster setting which, when
+// non-zero, makes the primary keys of new tables hash sharded with that many
+// buckets unless they are explicitly sharded.
+var DefaultPrimaryKeyHashShardBucketCount = settings.RegisterIntSetting(
+	settings.ApplicationLevel,
+	"sql.schema.default_primary_key_hash_shard_bucket_count",
+	"if non-zero, the primary keys of new tables are hash sharded with this many buckets "+
+		"unless specified otherwise in the primary key definition",
+	0,
+	settings.IntInRangeOrZeroDisable(2, MaxBucketAllowed),
+	settings.WithPublic)
+
 // GetShardColumnName generates a name for the hidden shard column to be used to create a
 // hash sharded index.
 func GetShardColumnName(colNames []string, buckets int32) string {
@@ -27,6 +27,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
 	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catenumpb"
@@ -2323,6 +2324,33 @@ func NewTableDesc(
 	return &desc, nil
 }
 
+// maybeHashShardPrimaryKey makes the primary key of the table being created
+// hash sharded if the sql.schema.default_primary_key_hash_shard_bucket_count
+// cluster setting is enabled and the primary key is not already explicitly
+// sharded. Tables which are partitioned, multi-region or created from a query
+// are left alone.
+func maybeHashShardPrimaryKey(
+	n *tree.CreateTable, db catalog.DatabaseDescriptor, sv *settings.Values,
+) {
+	buckets := tabledesc.DefaultPrimaryKeyHashShardBucketCount.Get(sv)
+	if buckets == 0 || n.As() || n.PartitionByTable != nil || n.Locality != nil || db.IsMultiRegion() {
+		return
+	}
+	for _, def := range n.Defs {
+		switch d := def.(type) {
+		case *tree.ColumnTableDef:
+			if d.PrimaryKey.IsPrimaryKey && !d.PrimaryKey.Sharded {
+				d.PrimaryKey.Sharded = true
+				d.PrimaryKey.ShardBuckets = tree.NewDInt(tree.DInt(buckets))
+			}
+		case *tree.UniqueConstraintTableDef:
+			if d.PrimaryKey && d.Sharded == nil && d.PartitionByIndex == nil {
+				d.Sharded = &tree.ShardedIndexDef{ShardBuckets: tree.NewDInt(tree.DInt(buckets))}
+			}
+		}
+	}
+}
+
 // newTableDesc creates a table descriptor from a CreateTable statement.
 func newTableDesc(
 	params runParams,
@@ -2338,6 +2366,8 @@ func newTableDesc(
 		return nil, err
 	}
 
+	maybeHashShardPrimaryKey(n, db, &params.ExecCfg().Settings.SV)
+
 	newDefs, err := replaceLikeTableOpts(n, params)
 	if err != nil {
 		return nil, err
@@ -55,7 +55,9 @@ var logStatementsExecuteEnabled = settings.RegisterBoolSetting(
 	settings.WithName("sql.log.all_statements.enabled"),
 	settings.WithPublic)
 
-var slowQueryLogThreshold = settings.RegisterDurationSettingWithExplicitUnit(
+// SlowQueryLogThreshold is the latency above which statements are logged to
+// the slow query log.
+var SlowQueryLogThreshold = settings.RegisterDurationSettingWithExplicitUnit(
 	settings.ApplicationLevel,
 	"sql.log.slow_query.latency_threshold",
 	"when set to non-zero, log statements whose service latency exceeds "+
@@ -174,7 +176,7 @@ func (p *planner) maybeLogStatementInternal(
 	// can't miss any statement.
 	logV := log.V(2)
 	logExecuteEnabled := logStatementsExecuteEnabled.Get(&p.execCfg.Settings.SV)
-	slowLogThreshold := slowQueryLogThreshold.Get(&p.execCfg.Settings.SV)
+	slowLogThreshold := SlowQueryLogThreshold.Get(&p.execCfg.Settings.SV)
 	slowLogFullTableScans := slowQueryLogFullTableScans.Get(&p.execCfg.Settings.SV)
 	slowQueryLogEnabled := slowLogThreshold != 0
 	slowInternalQueryLogEnabled := slowInternalQueryLogEnabled.Get(&p.execCfg.Settings.SV)
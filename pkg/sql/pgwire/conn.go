@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql"
@@ -111,6 +112,16 @@ type conn struct {
 
 	// alwaysLogAuthActivity is used force-enables logging of authn events.
 	alwaysLogAuthActivity bool
+
+	// exemptFromDrainFn is called with the authenticated user, and returns
+	// whether the connection is exempt from a server drain. See
+	// Server.registerConn.
+	exemptFromDrainFn func(username.SQLUsername) bool
+
+	// drainExempt is set once the connection is authenticated as one of the
+	// users whose sessions survive a drain. The connection is then not told
+	// to quit when the server drains.
+	drainExempt atomic.Bool
 }
 
 func (c *conn) setErr(err error) {
@@ -207,6 +218,9 @@ func (c *conn) processCommands(
 		// Auth failed or some other error.
 		return
 	}
+	if c.exemptFromDrainFn != nil && c.exemptFromDrainFn(c.sessionArgs.User) {
+		c.drainExempt.Store(true)
+	}
 
 	var decrementConnectionCount func()
 	if decrementConnectionCount, retErr = sqlServer.IncrementConnectionCount(c.sessionArgs); retErr != nil {
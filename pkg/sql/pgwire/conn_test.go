@@ -51,6 +51,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/metric"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 	"github.com/jackc/pgconn"
@@ -2351,3 +2352,41 @@ func TestConnCloseReleasesReservedMem(t *testing.T) {
 	after := s.PGServer().(*Server).tenantSpecificConnMonitor.AllocBytes()
 	require.Equal(t, before, after)
 }
+
+// TestDrainAllowedUsers checks that the sessions authenticated as one of the
+// drainAllowedUsers are kept open when the server drains, while other sessions
+// are canceled. The sessions that are kept open are still counted.
+func TestDrainAllowedUsers(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	s := newTestServer()
+	s.mu.connCancelMap = make(cancelChanMap)
+	s.mu.drainExemptConns = make(map[chan struct{}]struct{})
+	s.drainAllowedUsers = makeDrainAllowedUsers([]string{"Ops"})
+
+	opsUser, _ := username.MakeSQLUsernameFromUserInput("ops", username.PurposeValidation)
+	opsCtx, _, opsClose, opsExempt := s.registerConn(ctx)
+	defer opsClose()
+	appCtx, _, appClose, appExempt := s.registerConn(ctx)
+	// Emulate the connection of the other user closing once canceled.
+	go func() {
+		<-appCtx.Done()
+		appClose()
+	}()
+
+	// Both connections are registered before they authenticate.
+	require.Equal(t, 2, s.GetConnCancelMapLen())
+	require.True(t, opsExempt(opsUser))
+	require.False(t, appExempt(username.TestUserName()))
+	require.Equal(t, 1, s.GetConnCancelMapLen())
+	require.Equal(t, int64(2), s.tenantMetrics.Conns.Value())
+
+	require.NoError(t, s.drainImpl(ctx, 0 /* queryWait */, 10*time.Second /* cancelWait */, nil /* reporter */, stopper))
+	require.Error(t, appCtx.Err())
+	require.NoError(t, opsCtx.Err())
+}
@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/security/username"
 	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/settings"
@@ -62,7 +63,7 @@ import (
 //
 // The "results_buffer_size" connection parameter can be used to override this
 // default for an individual connection.
-var connResultsBufferSize = settings.RegisterByteSizeSetting(
+var ConnResultsBufferSize = settings.RegisterByteSizeSetting(
 	settings.ApplicationLevel,
 	"sql.defaults.results_buffer.size",
 	"default size of the buffer that accumulates results for a statement or a batch "+
@@ -234,6 +235,11 @@ type Server struct {
 		// cancel the associated connection. The corresponding key is a channel
 		// that is closed when the connection is done.
 		connCancelMap cancelChanMap
+		// drainExemptConns holds the keys of the connCancelMap entries whose
+		// connections authenticated as one of the drainAllowedUsers. These
+		// connections are counted in connCancelMap, but a drain neither waits
+		// for them nor cancels them.
+		drainExemptConns map[chan struct{}]struct{}
 		// draining is set to true when the server starts draining the SQL layer.
 		// When set to true, remaining SQL connections will be closed.
 		// After the timeout set by server.shutdown.transactions.timeout,
@@ -251,6 +257,10 @@ type Server struct {
 		identityMap *identmap.Conf
 	}
 
+	// drainAllowedUsers are the users whose sessions are kept open when the
+	// server drains. See base.Config.DrainAllowedUsers.
+	drainAllowedUsers map[username.SQLUsername]struct{}
+
 	// sqlMemoryPool is the parent memory pool for all SQL memory allocations
 	// for this tenant, including SQL query execution, etc.
 	sqlMemoryPool *mon.BytesMonitor
@@ -355,8 +365,11 @@ func MakeServer(
 
 	server.mu.Lock()
 	server.mu.connCancelMap = make(cancelChanMap)
+	server.mu.drainExemptConns = make(map[chan struct{}]struct{})
 	server.mu.Unlock()
 
+	server.drainAllowedUsers = makeDrainAllowedUsers(cfg.DrainAllowedUsers)
+
 	connAuthConf.SetOnChange(&st.SV, func(ctx context.Context) {
 		loadLocalHBAConfigUponRemoteSettingChange(ctx, server, st)
 	})
@@ -474,7 +487,8 @@ func (s *Server) setRejectNewConnectionsLocked(rej bool) {
 	s.mu.rejectNewConnections = rej
 }
 
-// GetConnCancelMapLen returns the length of connCancelMap of the server.
+// GetConnCancelMapLen returns the length of connCancelMap of the server,
+// excluding the connections of the drainAllowedUsers.
 // This is a helper function when the server waits the SQL connections to be
 // closed. During this period, the server listens to the status of all
 // connections, and early exits this draining phase if there remains no active
@@ -482,7 +496,7 @@ func (s *Server) setRejectNewConnectionsLocked(rej bool) {
 func (s *Server) GetConnCancelMapLen() int {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	return len(s.mu.connCancelMap)
+	return len(s.mu.connCancelMap) - len(s.mu.drainExemptConns)
 }
 
 // WaitForSQLConnsToClose waits for the client to close all SQL connections for the
@@ -532,15 +546,19 @@ func (s *Server) WaitForSQLConnsToClose(
 	return nil
 }
 
-// waitConnsDone returns a copy of s.mu.connCancelMap, and a channel that
-// will be closed once all sql connections are closed, or the server quits
-// waiting for connections, whichever earlier.
+// waitConnsDone returns a copy of s.mu.connCancelMap without the connections
+// of the drainAllowedUsers, and a channel that will be closed once all these
+// sql connections are closed, or the server quits waiting for connections,
+// whichever earlier.
 func (s *Server) waitConnsDone() (cancelChanMap, chan struct{}, chan struct{}) {
 	connCancelMap := func() cancelChanMap {
 		s.mu.Lock()
 		defer s.mu.Unlock()
 		connCancelMap := make(cancelChanMap, len(s.mu.connCancelMap))
 		for done, cancel := range s.mu.connCancelMap {
+			if _, ok := s.mu.drainExemptConns[done]; ok {
+				continue
+			}
 			connCancelMap[done] = cancel
 		}
 		return connCancelMap
@@ -723,7 +741,7 @@ func (s *Server) ServeConn(
 		}
 	}()
 
-	ctx, rejectNewConnections, onCloseFn := s.registerConn(ctx)
+	ctx, rejectNewConnections, onCloseFn, exemptFromDrainFn := s.registerConn(ctx)
 	defer onCloseFn()
 
 	sessionID := s.execCfg.GenerateID()
@@ -814,6 +832,7 @@ func (s *Server) ServeConn(
 		sArgs,
 		connStart,
 	)
+	c.exemptFromDrainFn = exemptFromDrainFn
 
 	// Do the reading of commands from the network.
 	s.serveImpl(
@@ -875,7 +894,7 @@ func (s *Server) newConn(
 		// If the server is draining, we'll let the processor know by pushing a
 		// DrainRequest. This will make the processor quit whenever it finds a good
 		// time.
-		if !sentDrainSignal && s.IsDraining() {
+		if !sentDrainSignal && !c.drainExempt.Load() && s.IsDraining() {
 			_ /* err */ = c.stmtBuf.Push(ctx, sql.DrainRequest{})
 			sentDrainSignal = true
 		}
@@ -1343,23 +1362,49 @@ func finalizeClientParameters(
 ) (sql.SessionArgs, error) {
 	// Inject the result buffer size if not defined by client.
 	if !cp.foundBufferSize && tenantSV != nil {
-		cp.ConnResultsBufferSize = connResultsBufferSize.Get(tenantSV)
+		cp.ConnResultsBufferSize = ConnResultsBufferSize.Get(tenantSV)
 	}
 
 	return cp.SessionArgs, nil
 }
 
+// makeDrainAllowedUsers returns the set of normalized user names whose
+// sessions survive a drain.
+func makeDrainAllowedUsers(users []string) map[username.SQLUsername]struct{} {
+	if len(users) == 0 {
+		return nil
+	}
+	allowed := make(map[username.SQLUsername]struct{}, len(users))
+	for _, u := range users {
+		// Validity of the names is checked when the server is configured.
+		user, _ := username.MakeSQLUsernameFromUserInput(u, username.PurposeValidation)
+		allowed[user] = struct{}{}
+	}
+	return allowed
+}
+
 // registerConn registers the incoming connection to the map of active connections,
 // which can be canceled by a concurrent server drain. It also returns a boolean
 // variable rejectConn, which shows if the server is rejecting new SQL
 // connections.
 //
+// The exemptFromDrainFn() callback must be called with the authenticated user
+// once the connection is authenticated. If the user is one of the
+// drainAllowedUsers, it exempts the connection from the drain and returns true.
+// The connection remains registered, so that it is still counted.
+//
 // The onCloseFn() callback must be called at the end of the
 // connection by the caller.
 func (s *Server) registerConn(
 	ctx context.Context,
-) (newCtx context.Context, rejectNewConnections bool, onCloseFn func()) {
+) (
+	newCtx context.Context,
+	rejectNewConnections bool,
+	onCloseFn func(),
+	exemptFromDrainFn func(username.SQLUsername) bool,
+) {
 	onCloseFn = func() {}
+	exemptFromDrainFn = func(username.SQLUsername) bool { return false }
 	newCtx = ctx
 	s.mu.Lock()
 	rejectNewConnections = s.mu.rejectNewConnections
@@ -1373,8 +1418,18 @@ func (s *Server) registerConn(
 			close(done)
 			s.mu.Lock()
 			delete(s.mu.connCancelMap, done)
+			delete(s.mu.drainExemptConns, done)
 			s.mu.Unlock()
 		}
+		exemptFromDrainFn = func(user username.SQLUsername) bool {
+			if _, ok := s.drainAllowedUsers[user]; !ok {
+				return false
+			}
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.mu.drainExemptConns[done] = struct{}{}
+			return true
+		}
 	}
 	s.mu.Unlock()
 
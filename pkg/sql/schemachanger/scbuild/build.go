@@ -43,6 +43,14 @@ var stubLogSchemaChangerEventsFn = func(ctx context.Context) error {
 	return nil
 }
 
+// DefaultMaxElements is the number of new target elements which building a
+// single statement may add, unless the Dependencies specify otherwise. The
+// elements of the existing descriptors which the statement references do not
+// count. It is far above what reasonable statements require, and only
+// protects the node against statements whose element graph would exhaust its
+// memory, e.g. some ALTERs of huge tables.
+const DefaultMaxElements = 1 << 20
+
 // Build constructs a new state from an incumbent state and a statement.
 //
 // The function takes an AST for a DDL statement and constructs targets
@@ -97,18 +105,25 @@ func Build(
 		SchemaFeatureChecker:    dependencies.FeatureChecker(),
 		TemporarySchemaProvider: dependencies.TemporarySchemaProvider(),
 	}
-	scbuildstmt.Process(b, an.GetStatement())
+	tracer := dependencies.PhaseTracer()
+	tracePhase(tracer, StatementBuildPhase, func() {
+		scbuildstmt.Process(b, an.GetStatement())
+	})
 
 	// Generate redacted statement.
-	{
+	tracePhase(tracer, ValidationPhase, func() {
 		an.ValidateAnnotations()
 		currentStatementID := uint32(len(els.statements) - 1)
 		els.statements[currentStatementID].RedactedStatement = string(
 			dependencies.AstFormatter().FormatAstAsRedactableString(an.GetStatement(), &an.annotation))
-	}
+	})
 
 	// Generate returned state.
-	ret, loggedTargets := makeState(dependencies.ClusterSettings().Version.ActiveVersion(ctx), bs)
+	var ret scpb.CurrentState
+	var loggedTargets []scpb.Target
+	tracePhase(tracer, ElementExpansionPhase, func() {
+		ret, loggedTargets = makeState(dependencies.ClusterSettings().Version.ActiveVersion(ctx), bs)
+	})
 	ret.Statements = els.statements
 	ret.Authorization = els.authorization
 
@@ -237,6 +252,11 @@ type builderState struct {
 	// output contains the schema change targets that have been planned so far.
 	output []elementState
 
+	// maxElements is the number of elements which the statement may add as
+	// new targets, and numAddedElements counts them. The elements decomposed
+	// from existing descriptors and the incumbent targets do not count.
+	maxElements, numAddedElements int
+
 	descCache      map[catid.DescID]*cachedDesc
 	tempSchemas    map[catid.DescID]catalog.SchemaDescriptor
 	newDescriptors catalog.DescriptorIDSet
@@ -307,6 +327,10 @@ func newBuilderState(
 			metadata: t.Metadata,
 		})
 	}
+	bs.maxElements = d.MaxElements()
+	if bs.maxElements <= 0 {
+		bs.maxElements = DefaultMaxElements
+	}
 	return &bs
 }
 
@@ -215,10 +215,27 @@ func (b *builderState) upsertElementState(es elementState) {
 		}
 		*existing = es
 	} else {
+		b.checkMaxElements()
 		b.addNewElementState(es)
 	}
 }
 
+// checkMaxElements counts an element added by the statement, and panics if the
+// statement adds more than maxElements of them.
+func (b *builderState) checkMaxElements() {
+	if b.maxElements <= 0 {
+		// The incumbent targets are still being loaded.
+		return
+	}
+	b.numAddedElements++
+	if b.numAddedElements > b.maxElements {
+		panic(errors.WithHint(
+			pgerror.Newf(pgcode.ProgramLimitExceeded,
+				"schema change statement adds more than %d elements", b.maxElements),
+			"Consider splitting the statement into several smaller statements."))
+	}
+}
+
 func (b *builderState) getExistingElementState(e scpb.Element) *elementState {
 	if e == nil {
 		panic(errors.AssertionFailedf("cannot define target for nil element"))
@@ -512,6 +529,14 @@ func (b *builderState) IsTableEmpty(table *scpb.Table) bool {
 	return b.tr.IsTableEmpty(b.ctx, table.TableID, index.IndexID)
 }
 
+// FindCheckConstraintViolation implements the scbuildstmt.TableHelpers
+// interface.
+func (b *builderState) FindCheckConstraintViolation(
+	table *scpb.Table, columns []string, expr string,
+) tree.Datums {
+	return b.tr.FindCheckConstraintViolation(b.ctx, table.TableID, columns, expr)
+}
+
 func (b *builderState) nextIndexID(id catid.DescID) (ret catid.IndexID) {
 	{
 		b.ensureDescriptor(id)
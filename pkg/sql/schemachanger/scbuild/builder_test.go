@@ -28,6 +28,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/rel"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scbuild"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scdeps/sctestdeps"
@@ -35,6 +38,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/screl"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/eval"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
 	"github.com/cockroachdb/cockroach/pkg/testutils/datapathutils"
@@ -45,6 +49,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/datadriven"
+	"github.com/cockroachdb/errors"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 )
@@ -326,3 +331,158 @@ func TestBuildIsMemoryMonitored(t *testing.T) {
 		require.ErrorContainsf(t, err, `test-sc-build-mon: memory budget exceeded:`, "got a memory usage of: %d", memAcc.Allocated())
 	})
 }
+
+// tracedDependencies overrides the PhaseTracer of the wrapped dependencies.
+type tracedDependencies struct {
+	scbuild.Dependencies
+	tracer scbuild.PhaseTracer
+}
+
+// PhaseTracer implements the scbuild.Dependencies interface.
+func (d tracedDependencies) PhaseTracer() scbuild.PhaseTracer {
+	return d.tracer
+}
+
+// TestBuildPhaseTimings tests that the build function reports the time spent
+// in each of its phases when a PhaseTracer is provided.
+func TestBuildPhaseTimings(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	tdb := sqlutils.MakeSQLRunner(db)
+	tdb.Exec(t, `CREATE TABLE defaultdb.t (i INT PRIMARY KEY, j INT)`)
+
+	sctestutils.WithBuilderDependenciesFromTestServer(s.ApplicationLayer(), s.NodeID(), func(dependencies scbuild.Dependencies) {
+		stmt, err := parser.ParseOne(`ALTER TABLE defaultdb.t ADD COLUMN k INT NOT NULL DEFAULT 42`)
+		require.NoError(t, err)
+		var timings scbuild.PhaseTimings
+		deps := tracedDependencies{Dependencies: dependencies, tracer: &timings}
+		state, _, err := scbuild.Build(ctx, deps, scpb.CurrentState{}, stmt.AST, mon.NewStandaloneUnlimitedAccount())
+		require.NoError(t, err)
+		require.Greater(t, len(state.Targets), 1)
+		for _, phase := range []scbuild.BuildPhase{
+			scbuild.StatementBuildPhase,
+			scbuild.ValidationPhase,
+			scbuild.ElementExpansionPhase,
+		} {
+			require.NotZerof(t, timings.Get(phase), "no timing recorded for %s", phase)
+		}
+		t.Logf("build phase timings: %s", &timings)
+	})
+}
+
+// cappedDependencies overrides the MaxElements of the wrapped dependencies.
+type cappedDependencies struct {
+	scbuild.Dependencies
+	maxElements int
+}
+
+// MaxElements implements the scbuild.Dependencies interface.
+func (d cappedDependencies) MaxElements() int {
+	return d.maxElements
+}
+
+// TestBuildMaxElements tests that the build function fails once a statement
+// adds more elements than the configured maximum, and that the elements of the
+// existing descriptors which the statement references do not count.
+func TestBuildMaxElements(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	// The table decomposes into far more than maxElements elements.
+	const maxElements = 10
+	var cols []string
+	for i := 0; i < 4*maxElements; i++ {
+		cols = append(cols, fmt.Sprintf("c%d INT", i))
+	}
+	tdb := sqlutils.MakeSQLRunner(db)
+	tdb.Exec(t, fmt.Sprintf(`CREATE TABLE defaultdb.t (i INT PRIMARY KEY, %s)`, strings.Join(cols, ", ")))
+
+	sctestutils.WithBuilderDependenciesFromTestServer(s.ApplicationLayer(), s.NodeID(), func(dependencies scbuild.Dependencies) {
+		deps := cappedDependencies{Dependencies: dependencies, maxElements: maxElements}
+		build := func(sql string) error {
+			stmt, err := parser.ParseOne(sql)
+			require.NoError(t, err)
+			_, _, err = scbuild.Build(ctx, deps, scpb.CurrentState{}, stmt.AST, mon.NewStandaloneUnlimitedAccount())
+			return err
+		}
+
+		// A statement adding few elements to the large table fits.
+		require.NoError(t, build(`COMMENT ON TABLE defaultdb.t IS 'large'`))
+
+		// Adding a column with a default rebuilds the primary index, which adds
+		// an element for each of its columns.
+		err := build(`ALTER TABLE defaultdb.t ADD COLUMN m INT NOT NULL DEFAULT 42`)
+		require.Error(t, err)
+		require.Equal(t, pgcode.ProgramLimitExceeded, pgerror.GetPGCode(err))
+		require.ErrorContains(t, err, "adds more than 10 elements")
+		require.Contains(t, errors.FlattenHints(err), "splitting the statement")
+
+		// The default maximum leaves plenty of room.
+		stmt, err := parser.ParseOne(`ALTER TABLE defaultdb.t ADD COLUMN m INT NOT NULL DEFAULT 42`)
+		require.NoError(t, err)
+		_, _, err = scbuild.Build(ctx, dependencies, scpb.CurrentState{}, stmt.AST, mon.NewStandaloneUnlimitedAccount())
+		require.NoError(t, err)
+	})
+}
+
+// noticeRecordingDependencies overrides the ClientNoticeSender of the wrapped
+// dependencies with one which records the notices.
+type noticeRecordingDependencies struct {
+	scbuild.Dependencies
+	notices *[]pgnotice.Notice
+}
+
+var _ eval.ClientNoticeSender = noticeRecordingDependencies{}
+
+// ClientNoticeSender implements the scbuild.Dependencies interface.
+func (d noticeRecordingDependencies) ClientNoticeSender() eval.ClientNoticeSender {
+	return d
+}
+
+// BufferClientNotice implements the eval.ClientNoticeSender interface.
+func (d noticeRecordingDependencies) BufferClientNotice(_ context.Context, notice pgnotice.Notice) {
+	*d.notices = append(*d.notices, notice)
+}
+
+// SendClientNotice implements the eval.ClientNoticeSender interface.
+func (d noticeRecordingDependencies) SendClientNotice(
+	ctx context.Context, notice pgnotice.Notice,
+) error {
+	d.BufferClientNotice(ctx, notice)
+	return nil
+}
+
+// TestBuildNoOpStatement tests that statements which don't change anything
+// produce no targets and notify the client instead.
+func TestBuildNoOpStatement(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	tdb := sqlutils.MakeSQLRunner(db)
+	tdb.Exec(t, `CREATE TABLE defaultdb.t (i INT PRIMARY KEY, j INT)`)
+
+	sctestutils.WithBuilderDependenciesFromTestServer(s.ApplicationLayer(), s.NodeID(), func(dependencies scbuild.Dependencies) {
+		stmt, err := parser.ParseOne(`ALTER TABLE defaultdb.t ADD COLUMN IF NOT EXISTS j INT`)
+		require.NoError(t, err)
+		var notices []pgnotice.Notice
+		deps := noticeRecordingDependencies{Dependencies: dependencies, notices: &notices}
+		state, _, err := scbuild.Build(ctx, deps, scpb.CurrentState{}, stmt.AST, mon.NewStandaloneUnlimitedAccount())
+		require.NoError(t, err)
+		require.Empty(t, state.Targets)
+		require.Len(t, notices, 1)
+		require.Equal(t, `column "j" of relation "t" already exists, skipping`, notices[0].Error())
+	})
+}
@@ -41,7 +41,10 @@ type Dependencies interface {
 	scbuildstmt.ClusterAndSessionInfo
 	scbuildstmt.Telemetry
 
-	// CatalogReader returns a CatalogReader implementation.
+	// CatalogReader returns a CatalogReader implementation. The SQL
+	// implementation reads descriptors in the statement's transaction without
+	// leases, so the build sees a single snapshot of the catalog even while
+	// concurrent schema changes commit.
 	CatalogReader() CatalogReader
 
 	// TableReader returns a TableReader implementation.
@@ -91,6 +94,14 @@ type Dependencies interface {
 	ReferenceProviderFactory() ReferenceProviderFactory
 
 	TemporarySchemaProvider() TemporarySchemaProvider
+
+	// PhaseTracer returns a PhaseTracer to be notified of the time spent in
+	// each phase of Build, or nil if no tracing is desired.
+	PhaseTracer() PhaseTracer
+
+	// MaxElements returns the maximum number of new target elements which
+	// building a single statement may add, or zero for DefaultMaxElements.
+	MaxElements() int
 }
 
 // CreatePartitioningCCLCallback is the type of the CCL callback for creating
@@ -170,6 +181,11 @@ type CatalogReader interface {
 type TableReader interface {
 	// IsTableEmpty returns if the table is empty.
 	IsTableEmpty(ctx context.Context, id descpb.ID, primaryIndexID descpb.IndexID) bool
+
+	// FindCheckConstraintViolation returns the values of the given columns for
+	// the first row of the table for which the check constraint expression
+	// evaluates to false, or nil if there is no such row.
+	FindCheckConstraintViolation(ctx context.Context, id descpb.ID, columns []string, expr string) tree.Datums
 }
 
 // AuthorizationAccessor for checking authorization (e.g. desc privileges).
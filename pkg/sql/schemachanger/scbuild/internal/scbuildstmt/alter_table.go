@@ -129,6 +129,15 @@ func AlterTable(b BuildCtx, n *tree.AlterTable) {
 	tn.ObjectNamePrefix = b.NamePrefix(tbl)
 	b.SetUnresolvedNameAnnotation(n.Table, &tn)
 	b.IncrementSchemaChangeAlterCounter("table")
+	// Short-circuit statements which don't change anything in the current state
+	// of the table, so that no elements get built for them.
+	if alterTableIsNoOp(b, tbl, n) {
+		for _, cmd := range n.Cmds {
+			b.IncrementSchemaChangeAlterCounter("table", cmd.TelemetryName())
+			noticeAlterTableNoOp(b, &tn, cmd)
+		}
+		return
+	}
 	for _, cmd := range n.Cmds {
 		// Invoke the callback function for each command.
 		b.IncrementSchemaChangeAlterCounter("table", cmd.TelemetryName())
@@ -147,6 +156,35 @@ func AlterTable(b BuildCtx, n *tree.AlterTable) {
 	disallowDroppingPrimaryIndexReferencedInUDFOrView(b, tbl.TableID, n.String())
 }
 
+// alterTableIsNoOp returns whether none of the commands in the ALTER TABLE
+// statement would change the table, e.g. when all of them are ADD COLUMN IF
+// NOT EXISTS commands for columns which already exist.
+func alterTableIsNoOp(b BuildCtx, tbl *scpb.Table, n *tree.AlterTable) bool {
+	if len(n.Cmds) == 0 {
+		return false
+	}
+	for _, cmd := range n.Cmds {
+		switch t := cmd.(type) {
+		case *tree.AlterTableAddColumn:
+			if !alterTableAddColumnIsNoOp(b, tbl, t) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// noticeAlterTableNoOp notifies the client that the ALTER TABLE command,
+// which alterTableIsNoOp found to be a no-op, was skipped.
+func noticeAlterTableNoOp(b BuildCtx, tn *tree.TableName, cmd tree.AlterTableCmd) {
+	switch t := cmd.(type) {
+	case *tree.AlterTableAddColumn:
+		noticeColumnAlreadyExists(b, tn, t.ColumnDef.Name)
+	}
+}
+
 // disallowDroppingPrimaryIndexReferencedInUDFOrView prevents dropping old (current)
 // primary index that is referenced explicitly via index hinting in UDF or View body.
 func disallowDroppingPrimaryIndexReferencedInUDFOrView(
@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scdecomp"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scerrors"
@@ -41,6 +42,27 @@ import (
 	"github.com/lib/pq/oid"
 )
 
+// alterTableAddColumnIsNoOp returns whether the ADD COLUMN command has an IF
+// NOT EXISTS clause and the column already exists.
+func alterTableAddColumnIsNoOp(b BuildCtx, tbl *scpb.Table, t *tree.AlterTableAddColumn) bool {
+	if !t.IfNotExists {
+		return false
+	}
+	elts := b.ResolveColumn(tbl.TableID, t.ColumnDef.Name, ResolveParams{
+		IsExistenceOptional: true,
+		RequiredPrivilege:   privilege.CREATE,
+	})
+	_, _, col := scpb.FindColumn(elts)
+	return col != nil
+}
+
+// noticeColumnAlreadyExists notifies the client that an ADD COLUMN IF NOT
+// EXISTS command was skipped.
+func noticeColumnAlreadyExists(b BuildCtx, tn *tree.TableName, name tree.Name) {
+	b.EvalCtx().ClientNoticeSender.BufferClientNotice(b, pgnotice.Newf(
+		"column %q of relation %q already exists, skipping", name, tn.Object()))
+}
+
 func alterTableAddColumn(
 	b BuildCtx, tn *tree.TableName, tbl *scpb.Table, t *tree.AlterTableAddColumn,
 ) {
@@ -62,6 +84,7 @@ func alterTableAddColumn(
 		_, colTargetStatus, col := scpb.FindColumn(elts)
 		if col != nil {
 			if t.IfNotExists {
+				noticeColumnAlreadyExists(b, tn, d.Name)
 				return
 			}
 			if col.IsSystemColumn {
@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
@@ -37,6 +38,16 @@ import (
 	"github.com/cockroachdb/errors"
 )
 
+// eagerCheckConstraintValidation controls whether `ALTER TABLE ... ADD CHECK`
+// scans the table for violating rows while building the schema change,
+// instead of deferring the validation to the post-commit phase.
+var eagerCheckConstraintValidation = settings.RegisterBoolSetting(
+	settings.ApplicationLevel,
+	"sql.schemachanger.eager_check_constraint_validation.enabled",
+	"validate new check constraints against existing rows when the schema change is planned",
+	false,
+)
+
 func alterTableAddConstraint(
 	b BuildCtx, tn *tree.TableName, tbl *scpb.Table, t *tree.AlterTableAddConstraint,
 ) {
@@ -127,7 +138,14 @@ func alterTableAddCheck(
 		panic(err)
 	}
 
-	// 3. Add relevant check constraint element:
+	// 3. If eager validation is enabled, look for an existing row violating
+	// the constraint now rather than in the validation phase of the job.
+	if t.ValidationBehavior == tree.ValidationDefault &&
+		eagerCheckConstraintValidation.Get(&b.ClusterSettings().SV) {
+		validateCheckEagerly(b, tbl, ckExpr, colIDs)
+	}
+
+	// 4. Add relevant check constraint element:
 	// - CheckConstraint or CheckConstraintUnvalidated
 	// - ConstraintName
 	constraintID := b.NextTableConstraintID(tbl.TableID)
@@ -164,6 +182,44 @@ func alterTableAddCheck(
 	})
 }
 
+// validateCheckEagerly scans the table for a row which violates the check
+// constraint expression and panics with a CheckViolation error reporting the
+// predicate and the offending row if it finds one. Expressions referencing
+// columns which are not public yet are left to the validation phase, as those
+// columns have not been backfilled.
+func validateCheckEagerly(
+	b BuildCtx, tbl *scpb.Table, ckExpr string, colIDs catalog.TableColSet,
+) {
+	for _, colID := range colIDs.Ordered() {
+		if current, _, _ := retrieveColumnElemAndStatus(b, tbl.TableID, colID); current != scpb.Status_PUBLIC {
+			return
+		}
+	}
+	var colNames []string
+	scpb.ForEachColumn(b.QueryByID(tbl.TableID).Filter(publicStatusFilter), func(
+		_ scpb.Status, _ scpb.TargetStatus, e *scpb.Column,
+	) {
+		if !e.IsInaccessible {
+			colNames = append(colNames, mustRetrieveColumnNameElem(b, tbl.TableID, e.ColumnID).Name)
+		}
+	})
+	violatingRow := b.FindCheckConstraintViolation(tbl, colNames, ckExpr)
+	if violatingRow == nil {
+		return
+	}
+	var buf strings.Builder
+	for i, name := range colNames {
+		if i != 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(name)
+		buf.WriteString("=")
+		buf.WriteString(violatingRow[i].String())
+	}
+	panic(pgerror.Newf(pgcode.CheckViolation,
+		"validation of CHECK %q failed on row: %s", ckExpr, buf.String()))
+}
+
 // getIndexIDForValidationForConstraint returns the index ID this check
 // constraint is supposed to check against and will be used to hint the
 // constraint validation query in `backfill.go`.
@@ -303,6 +303,11 @@ type TableHelpers interface {
 
 	// IsTableEmpty returns if the table is empty or not.
 	IsTableEmpty(tbl *scpb.Table) bool
+
+	// FindCheckConstraintViolation returns the values of the given columns for
+	// the first row of the table violating the check constraint expression, or
+	// nil if all rows satisfy it.
+	FindCheckConstraintViolation(tbl *scpb.Table, columns []string, expr string) tree.Datums
 }
 
 type FunctionHelpers interface {
@@ -0,0 +1,106 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package scbuild
+
+import (
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/redact"
+)
+
+// BuildPhase identifies a phase of Build for the purpose of tracing.
+type BuildPhase int
+
+const (
+	// StatementBuildPhase covers the processing of the statement AST into
+	// element targets.
+	StatementBuildPhase BuildPhase = iota
+	// ValidationPhase covers the validation of the AST annotations and the
+	// generation of the redacted statement.
+	ValidationPhase
+	// ElementExpansionPhase covers the expansion of the builder state into the
+	// targets and statuses of the returned state.
+	ElementExpansionPhase
+
+	numBuildPhases
+)
+
+// SafeValue implements the redact.SafeValue interface.
+func (p BuildPhase) SafeValue() {}
+
+// String implements the fmt.Stringer interface.
+func (p BuildPhase) String() string {
+	switch p {
+	case StatementBuildPhase:
+		return "statement build"
+	case ValidationPhase:
+		return "validation"
+	case ElementExpansionPhase:
+		return "element expansion"
+	default:
+		return "unknown"
+	}
+}
+
+// PhaseTracer is notified of the time spent in each phase of Build.
+type PhaseTracer interface {
+	// RecordPhase is called once the given phase has completed.
+	RecordPhase(phase BuildPhase, duration time.Duration)
+}
+
+// PhaseTimings is a PhaseTracer which accumulates the time spent in each
+// phase, suitable for logging once Build returns.
+type PhaseTimings [numBuildPhases]time.Duration
+
+var _ PhaseTracer = (*PhaseTimings)(nil)
+
+// RecordPhase implements the PhaseTracer interface.
+func (pt *PhaseTimings) RecordPhase(phase BuildPhase, duration time.Duration) {
+	if phase < 0 || phase >= numBuildPhases {
+		return
+	}
+	pt[phase] += duration
+}
+
+// Get returns the time accumulated for the given phase.
+func (pt *PhaseTimings) Get(phase BuildPhase) time.Duration {
+	if phase < 0 || phase >= numBuildPhases {
+		return 0
+	}
+	return pt[phase]
+}
+
+// SafeFormat implements the redact.SafeFormatter interface.
+func (pt *PhaseTimings) SafeFormat(w redact.SafePrinter, _ rune) {
+	for phase := BuildPhase(0); phase < numBuildPhases; phase++ {
+		if phase > 0 {
+			w.SafeString(", ")
+		}
+		w.Printf("%s: %s", phase, pt[phase])
+	}
+}
+
+// String implements the fmt.Stringer interface.
+func (pt *PhaseTimings) String() string {
+	return redact.StringWithoutMarkers(pt)
+}
+
+// tracePhase runs fn and reports its duration to tracer, if not nil.
+func tracePhase(tracer PhaseTracer, phase BuildPhase, fn func()) {
+	if tracer == nil {
+		fn()
+		return
+	}
+	start := timeutil.Now()
+	fn()
+	tracer.RecordPhase(phase, timeutil.Since(start))
+}
@@ -12,6 +12,7 @@ package scdeps
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
@@ -24,6 +25,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/nstree"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/isql"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/schemachanger/scbuild"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/catconstants"
@@ -61,6 +63,7 @@ func NewBuilderDependencies(
 		clusterID:       clusterID,
 		codec:           codec,
 		txn:             txn.KV(),
+		sqlTxn:          txn,
 		descsCollection: txn.Descriptors(),
 		authAccessor:    authAccessor,
 		sessionData:     sessionData,
@@ -83,6 +86,7 @@ type buildDeps struct {
 	clusterID                uuid.UUID
 	codec                    keys.SQLCodec
 	txn                      *kv.Txn
+	sqlTxn                   isql.Txn
 	descsCollection          *descs.Collection
 	schemaResolver           resolver.SchemaResolver
 	authAccessor             scbuild.AuthorizationAccessor
@@ -319,6 +323,29 @@ func (d *buildDeps) IsTableEmpty(
 	return len(kvs) == 0
 }
 
+// FindCheckConstraintViolation implements the scbuild.TableReader interface.
+func (d *buildDeps) FindCheckConstraintViolation(
+	ctx context.Context, id descpb.ID, columns []string, expr string,
+) tree.Datums {
+	colNames := make(tree.NameList, len(columns))
+	for i, c := range columns {
+		colNames[i] = tree.Name(c)
+	}
+	query := fmt.Sprintf(`SELECT %s FROM [%d AS t] WHERE NOT (%s) LIMIT 1`,
+		tree.AsStringWithFlags(&colNames, tree.FmtSerializable), id, expr)
+	row, err := d.sqlTxn.QueryRowEx(
+		ctx,
+		"validate check constraint",
+		d.txn,
+		sessiondata.NodeUserSessionDataOverride,
+		query,
+	)
+	if err != nil {
+		panic(err)
+	}
+	return row
+}
+
 // CreatePartitioningCCL is the public hook point for the CCL-licensed
 // partitioning creation code.
 var CreatePartitioningCCL scbuild.CreatePartitioningCCLCallback
@@ -494,3 +521,13 @@ func (d *buildDeps) ReferenceProviderFactory() scbuild.ReferenceProviderFactory
 func (d *buildDeps) TemporarySchemaProvider() scbuild.TemporarySchemaProvider {
 	return d.temporarySchemaProvider
 }
+
+// PhaseTracer implements the scbuild.Dependencies interface.
+func (d *buildDeps) PhaseTracer() scbuild.PhaseTracer {
+	return nil
+}
+
+// MaxElements implements the scbuild.Dependencies interface.
+func (d *buildDeps) MaxElements() int {
+	return scbuild.DefaultMaxElements
+}
@@ -169,6 +169,21 @@ func WithReferenceProviderFactory(f scbuild.ReferenceProviderFactory) Option {
 	})
 }
 
+// WithPhaseTracer sets the scbuild.PhaseTracer notified of build phases.
+func WithPhaseTracer(tracer scbuild.PhaseTracer) Option {
+	return optionFunc(func(state *TestState) {
+		state.phaseTracer = tracer
+	})
+}
+
+// WithMaxElements sets the maximum number of new target elements which
+// building a statement may add.
+func WithMaxElements(n int) Option {
+	return optionFunc(func(state *TestState) {
+		state.maxElements = n
+	})
+}
+
 var (
 	// defaultOverriddenCreatedAt is used to populate the CreatedAt timestamp for
 	// all descriptors injected into the catalog. We inject this to make the
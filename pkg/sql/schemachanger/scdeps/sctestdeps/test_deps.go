@@ -1225,6 +1225,13 @@ func (s *TestState) IsTableEmpty(
 	return true
 }
 
+// FindCheckConstraintViolation implement scbuild.TableReader.
+func (s *TestState) FindCheckConstraintViolation(
+	ctx context.Context, id descpb.ID, columns []string, expr string,
+) tree.Datums {
+	return nil
+}
+
 // TableReader implement scexec.Dependencies.
 func (s *TestState) TableReader() scbuild.TableReader {
 	return s
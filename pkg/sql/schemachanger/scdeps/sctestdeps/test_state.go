@@ -84,6 +84,8 @@ type TestState struct {
 	catalogChanges     catalogChanges
 	idGenerator        eval.DescIDGenerator
 	refProviderFactory scbuild.ReferenceProviderFactory
+	phaseTracer        scbuild.PhaseTracer
+	maxElements        int
 }
 
 type catalogChanges struct {
@@ -249,6 +251,16 @@ func (s *TestState) ReferenceProviderFactory() scbuild.ReferenceProviderFactory
 	return s.refProviderFactory
 }
 
+// PhaseTracer implements scbuild.Dependencies.
+func (s *TestState) PhaseTracer() scbuild.PhaseTracer {
+	return s.phaseTracer
+}
+
+// MaxElements implements scbuild.Dependencies.
+func (s *TestState) MaxElements() int {
+	return s.maxElements
+}
+
 func (s *TestState) descriptorDiff(desc catalog.Descriptor) string {
 	var old protoutil.Message
 	if d, _ := s.mustReadImmutableDescriptor(desc.GetID()); d != nil {
@@ -176,6 +176,51 @@ func TestSchemaChangerJobErrorDetails(t *testing.T) {
 	require.EqualValues(t, [][]string{{"boom"}}, results)
 }
 
+// TestEagerCheckConstraintValidation tests that, when eager validation is
+// enabled, adding a check constraint which existing rows violate fails when
+// the statement is executed instead of when the transaction commits.
+func TestEagerCheckConstraintValidation(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	tdb := sqlutils.MakeSQLRunner(sqlDB)
+	tdb.Exec(t, `CREATE TABLE t (k INT PRIMARY KEY, v INT)`)
+	tdb.Exec(t, `INSERT INTO t VALUES (1, 1), (2, -2)`)
+
+	addCheck := func(t *testing.T) (execErr, commitErr error) {
+		tx, err := sqlDB.Begin()
+		require.NoError(t, err)
+		_, err = tx.Exec(`SET LOCAL use_declarative_schema_changer = 'unsafe_always'`)
+		require.NoError(t, err)
+		if _, execErr = tx.Exec(`ALTER TABLE t ADD CONSTRAINT v_pos CHECK (v > 0)`); execErr != nil {
+			require.NoError(t, tx.Rollback())
+			return execErr, nil
+		}
+		return nil, tx.Commit()
+	}
+
+	t.Run("deferred", func(t *testing.T) {
+		execErr, commitErr := addCheck(t)
+		require.NoError(t, execErr)
+		require.Error(t, commitErr)
+		require.Regexp(t, `validation of CHECK`, commitErr.Error())
+	})
+
+	t.Run("eager", func(t *testing.T) {
+		tdb.Exec(t, `SET CLUSTER SETTING sql.schemachanger.eager_check_constraint_validation.enabled = true`)
+		execErr, _ := addCheck(t)
+		require.Error(t, execErr)
+		var pqErr *pq.Error
+		require.True(t, errors.As(execErr, &pqErr))
+		require.Equal(t, pgcode.CheckViolation.String(), string(pqErr.Code))
+		require.Equal(t, `validation of CHECK "v > 0:::INT8" failed on row: k=2, v=-2`, pqErr.Message)
+	})
+}
+
 func TestInsertDuringAddColumnNotWritingToCurrentPrimaryIndex(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -113,6 +113,12 @@ var TargetBytesPerLockConflictError = settings.RegisterIntSetting(
 // okay, because Engine construction in NewPebble will invoke it and store the
 // value on the Engine itself.
 func getMaxConcurrentCompactions() int {
+	return maxConcurrentCompactionsForStores(1)
+}
+
+// maxConcurrentCompactionsForStores is like getMaxConcurrentCompactions, for
+// each of numStores stores sharing the node's CPUs.
+func maxConcurrentCompactionsForStores(numStores int) int {
 	n := envutil.EnvOrDefaultInt(
 		"COCKROACH_CONCURRENT_COMPACTIONS", func() int {
 			// The old COCKROACH_ROCKSDB_CONCURRENCY environment variable was never
@@ -132,13 +138,9 @@ func getMaxConcurrentCompactions() int {
 				return oldV - 1
 			}
 
-			// By default use up to min(numCPU-1, 3) threads for background
-			// compactions per store (reserving the final process for flushes).
-			const max = 3
-			if n := runtime.GOMAXPROCS(0); n-1 < max {
-				return n - 1
-			}
-			return max
+			// By default, use the background jobs other than the one reserved
+			// for flushes for compactions.
+			return defaultMaxBackgroundJobs(runtime.GOMAXPROCS(0), numStores) - 1
 		}())
 	if n < 1 {
 		return 1
@@ -146,6 +148,16 @@ func getMaxConcurrentCompactions() int {
 	return n
 }
 
+// defaultMaxBackgroundJobs returns the default number of background jobs,
+// flushes and compactions combined, of each of numStores stores on a node with
+// numCPU CPUs. The CPUs are divided among the stores, and each store runs
+// between 2 and 4 jobs: a flush and up to 3 compactions. For a single store,
+// this is min(numCPU-1, 3) compactions.
+func defaultMaxBackgroundJobs(numCPU, numStores int) int {
+	const minJobs, maxJobs = 2, 4
+	return min(max(numCPU/max(numStores, 1), minJobs), maxJobs)
+}
+
 // l0SubLevelCompactionConcurrency is the sub-level threshold at which to
 // allow an increase in compaction concurrency. The maximum is still
 // controlled by pebble.Options.MaxConcurrentCompactions. The default of 2
@@ -142,6 +142,90 @@ func MaxOpenFiles(count int) ConfigOption {
 
 }
 
+// MaxWALSize bounds the size of the unflushed WAL, which is replayed when the
+// engine is reopened after a crash. The size must be larger than the memtable
+// size; the effective bound is rounded down to a multiple of it, with a floor
+// of two memtables.
+func MaxWALSize(size int64) ConfigOption {
+	return func(cfg *engineConfig) error {
+		if size <= 0 {
+			return errors.Errorf("max WAL size must be positive, got %d", size)
+		}
+		cfg.maxWALSize = size
+		return nil
+	}
+}
+
+// MaxBackgroundJobs bounds the number of background jobs, flushes and
+// compactions combined, which the engine runs concurrently. One job is
+// reserved for flushes, and at least one compaction may always run. It takes
+// precedence over the compaction concurrency configured through the
+// COCKROACH_CONCURRENT_COMPACTIONS environment variable.
+func MaxBackgroundJobs(n int) ConfigOption {
+	return func(cfg *engineConfig) error {
+		if n < 1 {
+			return errors.Errorf("max background jobs must be at least 1, got %d", n)
+		}
+		cfg.opts.MaxConcurrentCompactions = func() int {
+			return max(n-1, 1)
+		}
+		return nil
+	}
+}
+
+// StoreCount configures the engine as one of numStores stores on the node,
+// which share its CPUs for background work. Unless the compaction concurrency
+// is configured otherwise, it is divided among the stores.
+func StoreCount(numStores int) ConfigOption {
+	return func(cfg *engineConfig) error {
+		cfg.opts.MaxConcurrentCompactions = func() int {
+			return maxConcurrentCompactionsForStores(numStores)
+		}
+		return nil
+	}
+}
+
+// L0CompactionThreshold sets the L0 read amplification at which compactions of
+// L0 into the lower levels are triggered. It must be below the read
+// amplification at which writes stop.
+func L0CompactionThreshold(n int) ConfigOption {
+	return func(cfg *engineConfig) error {
+		if n < 1 {
+			return errors.Errorf("L0 compaction threshold must be positive, got %d", n)
+		}
+		if stop := cfg.opts.L0StopWritesThreshold; n >= stop {
+			return errors.Errorf("L0 compaction threshold %d must be less than the L0 stop writes threshold %d",
+				n, stop)
+		}
+		cfg.opts.L0CompactionThreshold = n
+		return nil
+	}
+}
+
+// BytesPerSync sets the number of bytes written to an sstable between
+// background syncs, which smooth out disk writes. Zero keeps the default.
+func BytesPerSync(n int64) ConfigOption {
+	return func(cfg *engineConfig) error {
+		if n < 0 {
+			return errors.Errorf("bytes per sync must be non-negative, got %d", n)
+		}
+		cfg.opts.BytesPerSync = int(n)
+		return nil
+	}
+}
+
+// WALBytesPerSync sets the number of bytes written to a WAL between
+// background syncs. Zero, the default, disables background WAL syncs.
+func WALBytesPerSync(n int64) ConfigOption {
+	return func(cfg *engineConfig) error {
+		if n < 0 {
+			return errors.Errorf("WAL bytes per sync must be non-negative, got %d", n)
+		}
+		cfg.opts.WALBytesPerSync = int(n)
+		return nil
+	}
+}
+
 // CacheSize configures the size of the block cache.
 func CacheSize(size int64) ConfigOption {
 	return func(cfg *engineConfig) error {
@@ -866,6 +866,9 @@ type engineConfig struct {
 	// maxSize is used for calculating free space and making rebalancing
 	// decisions. Zero indicates that there is no maximum size.
 	maxSize int64
+	// maxWALSize, if positive, bounds the size of the WAL that has not yet
+	// been flushed to sstables, and must be replayed on recovery.
+	maxWALSize int64
 	// If true, creating the instance fails if the target directory does not hold
 	// an initialized instance.
 	//
@@ -1094,6 +1097,18 @@ func newPebble(ctx context.Context, cfg engineConfig) (p *Pebble, err error) {
 
 	cfg.opts.EnsureDefaults()
 
+	if cfg.maxWALSize > 0 {
+		// The unflushed WAL consists of the logs backing the queued memtables,
+		// so bound it by reducing the number of memtables that may be queued
+		// before writes stall waiting for a flush. Pebble needs at least two.
+		if uint64(cfg.maxWALSize) <= cfg.opts.MemTableSize {
+			return nil, errors.Errorf("max WAL size %s must be larger than the memtable size %s",
+				humanizeutil.IBytes(cfg.maxWALSize), humanizeutil.IBytes(int64(cfg.opts.MemTableSize)))
+		}
+		threshold := max(2, int(uint64(cfg.maxWALSize)/cfg.opts.MemTableSize))
+		cfg.opts.MemTableStopWritesThreshold = min(cfg.opts.MemTableStopWritesThreshold, threshold)
+	}
+
 	// The context dance here is done so that we have a clean context without
 	// timeouts that has a copy of the log tags.
 	logCtx := logtags.WithTags(context.Background(), logtags.FromContext(ctx))
@@ -1893,6 +1908,12 @@ func (p *Pebble) Properties() roachpb.StoreProperties {
 	return p.properties
 }
 
+// OptionsString returns the options the engine was opened with, rendered in
+// the format of a Pebble OPTIONS file.
+func (p *Pebble) OptionsString() string {
+	return p.cfg.opts.String()
+}
+
 // Capacity implements the Engine interface.
 func (p *Pebble) Capacity() (roachpb.StoreCapacity, error) {
 	dir := p.cfg.env.Dir
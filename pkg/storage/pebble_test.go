@@ -1500,9 +1500,141 @@ func TestCompactionConcurrencyEnvVars(t *testing.T) {
 	}
 }
 
+func TestDefaultMaxBackgroundJobs(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	for _, tc := range []struct {
+		numCPU, numStores, want int
+	}{
+		{numCPU: 1, numStores: 1, want: 2},
+		{numCPU: 3, numStores: 1, want: 3},
+		{numCPU: 32, numStores: 1, want: 4},
+		{numCPU: 8, numStores: 2, want: 4},
+		{numCPU: 8, numStores: 3, want: 2},
+		{numCPU: 8, numStores: 8, want: 2},
+		{numCPU: 4, numStores: 0, want: 4},
+	} {
+		require.Equal(t, tc.want, defaultMaxBackgroundJobs(tc.numCPU, tc.numStores),
+			"numCPU=%d numStores=%d", tc.numCPU, tc.numStores)
+	}
+
+	// The CPUs are divided among the stores when the compaction concurrency
+	// is not configured through the environment.
+	defer envutil.TestUnsetEnv(t, "COCKROACH_ROCKSDB_CONCURRENCY")()
+	defer envutil.TestUnsetEnv(t, "COCKROACH_CONCURRENT_COMPACTIONS")()
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(12))
+	require.Equal(t, 3, maxConcurrentCompactionsForStores(1))
+	require.Equal(t, 2, maxConcurrentCompactionsForStores(4))
+	require.Equal(t, 1, maxConcurrentCompactionsForStores(12))
+	defer envutil.TestSetEnv(t, "COCKROACH_CONCURRENT_COMPACTIONS", "6")()
+	require.Equal(t, 6, maxConcurrentCompactionsForStores(12))
+}
+
+func TestPebbleMaxBackgroundJobs(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	// The explicit bound supersedes the environment.
+	defer envutil.TestSetEnv(t, "COCKROACH_CONCURRENT_COMPACTIONS", "6")()
+	for _, tc := range []struct {
+		jobs, wantCompactions int
+	}{
+		{jobs: 1, wantCompactions: 1},
+		{jobs: 2, wantCompactions: 1},
+		{jobs: 5, wantCompactions: 4},
+	} {
+		p, err := Open(ctx, InMemory(), cluster.MakeTestingClusterSettings(), MaxBackgroundJobs(tc.jobs))
+		require.NoError(t, err)
+		require.Equal(t, tc.wantCompactions, p.cfg.opts.MaxConcurrentCompactions())
+		p.Close()
+	}
+	_, err := Open(ctx, InMemory(), cluster.MakeTestingClusterSettings(), MaxBackgroundJobs(0))
+	require.ErrorContains(t, err, "max background jobs must be at least 1")
+}
+
+func TestPebbleL0CompactionThreshold(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	p, err := Open(ctx, InMemory(), cluster.MakeTestingClusterSettings(), L0CompactionThreshold(4))
+	require.NoError(t, err)
+	require.Equal(t, 4, p.cfg.opts.L0CompactionThreshold)
+	p.Close()
+
+	stop := DefaultPebbleOptions().L0StopWritesThreshold
+	for _, n := range []int{stop, stop + 1} {
+		_, err = Open(ctx, InMemory(), cluster.MakeTestingClusterSettings(), L0CompactionThreshold(n))
+		require.ErrorContains(t, err, "must be less than the L0 stop writes threshold")
+	}
+	_, err = Open(ctx, InMemory(), cluster.MakeTestingClusterSettings(), L0CompactionThreshold(0))
+	require.ErrorContains(t, err, "L0 compaction threshold must be positive")
+}
+
 func TestMinimumSupportedFormatVersion(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 
 	require.Equal(t, pebbleFormatVersionMap[clusterversion.MinSupported], MinimumSupportedFormatVersion,
 		"MinimumSupportedFormatVersion must match the format version for %s", clusterversion.MinSupported)
 }
+
+func TestPebbleMaxWALSize(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	memTableSize := DefaultPebbleOptions().MemTableSize
+	defaultThreshold := DefaultPebbleOptions().MemTableStopWritesThreshold
+	for _, tc := range []struct {
+		maxWALSize        int64
+		expectedThreshold int
+		expectedErr       string
+	}{
+		// Bounds below two memtables are raised to two memtables.
+		{maxWALSize: int64(memTableSize) + 1, expectedThreshold: 2},
+		{maxWALSize: 3 * int64(memTableSize), expectedThreshold: 3},
+		// Bounds above the current threshold leave it unchanged.
+		{maxWALSize: 100 * int64(memTableSize), expectedThreshold: defaultThreshold},
+		{maxWALSize: int64(memTableSize), expectedErr: "must be larger than the memtable size"},
+		{maxWALSize: 1 << 20, expectedErr: "must be larger than the memtable size"},
+		{maxWALSize: -1, expectedErr: "max WAL size must be positive"},
+	} {
+		t.Run(fmt.Sprint(tc.maxWALSize), func(t *testing.T) {
+			p, err := Open(ctx, InMemory(), cluster.MakeTestingClusterSettings(), MaxWALSize(tc.maxWALSize))
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			defer p.Close()
+			require.Equal(t, tc.expectedThreshold, p.cfg.opts.MemTableStopWritesThreshold)
+		})
+	}
+}
+
+func TestPebbleBytesPerSync(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	p, err := Open(ctx, InMemory(), cluster.MakeTestingClusterSettings(),
+		BytesPerSync(1<<20), WALBytesPerSync(256<<10))
+	require.NoError(t, err)
+	defer p.Close()
+	require.Equal(t, 1<<20, p.cfg.opts.BytesPerSync)
+	require.Equal(t, 256<<10, p.cfg.opts.WALBytesPerSync)
+
+	// Without the options, the engine keeps the Pebble defaults.
+	d, err := Open(ctx, InMemory(), cluster.MakeTestingClusterSettings())
+	require.NoError(t, err)
+	defer d.Close()
+	defaults := DefaultPebbleOptions()
+	defaults.EnsureDefaults()
+	require.Equal(t, defaults.BytesPerSync, d.cfg.opts.BytesPerSync)
+	require.Zero(t, d.cfg.opts.WALBytesPerSync)
+
+	_, err = Open(ctx, InMemory(), cluster.MakeTestingClusterSettings(), BytesPerSync(-1))
+	require.ErrorContains(t, err, "bytes per sync must be non-negative")
+}
@@ -117,6 +117,7 @@ func TestSingleNodeDocker(t *testing.T) {
 			runContainerArgs: runContainerArgs{
 				envSetting: []string{
 					"COCKROACH_DATABASE=mydb",
+					"COCKROACH_ACCEPT_INSECURE_EXPOSURE=true",
 				},
 				volSetting: []string{
 					fmt.Sprintf("%s/testdata/single-node-test/docker-entrypoint-initdb.d/:/docker-entrypoint-initdb.d", pwd),
@@ -144,6 +145,7 @@ func TestSingleNodeDocker(t *testing.T) {
 			runContainerArgs: runContainerArgs{
 				envSetting: []string{
 					"COCKROACH_DATABASE=mydb",
+					"COCKROACH_ACCEPT_INSECURE_EXPOSURE=true",
 				},
 				volSetting: []string{
 					fmt.Sprintf("%s/testdata/single-node-test/docker-entrypoint-initdb.d/:/docker-entrypoint-initdb.d", pwd),
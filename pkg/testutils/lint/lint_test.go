@@ -630,6 +630,7 @@ func TestLint(t *testing.T) {
 					":!roachprod",                               // roachprod requires AWS environment variables
 					":!cli/env.go",                              // The CLI needs the PGHOST variable.
 					":!cli/start.go",                            // The CLI needs the GOMEMLIMIT and GOGC variables.
+					":!base/store_spec.go",                      // Store paths may reference environment variables.
 					":!internal/codeowners/codeowners.go",       // For BAZEL_TEST.
 					":!internal/team/team.go",                   // For BAZEL_TEST.
 					":!util/log/test_log_scope.go",              // For TEST_UNDECLARED_OUTPUT_DIR, REMOTE_EXEC
@@ -137,7 +137,7 @@ func newElasticCPUGranterWithTokenBucket(
 		metrics: metrics,
 	}
 	e.mu.tb = tokenBucket
-	e.setUtilizationLimit(elasticCPUMinUtilization.Get(&st.SV))
+	e.setUtilizationLimit(ElasticCPUMinUtilization.Get(&st.SV))
 	return e
 }
 
@@ -662,6 +662,10 @@ type StoreMetrics struct {
 	WriteStallCount int64
 	// Optional.
 	DiskStats DiskStats
+	// IOTokenRate, if positive, bounds the rate in bytes/s at which IO tokens
+	// are given out to work writing to the store, independently of how much
+	// the LSM could absorb.
+	IOTokenRate int64
 }
 
 // DiskStats provide low-level stats about the disk resources used for a
@@ -634,6 +634,12 @@ func (io *ioLoadListener) adjustTokens(ctx context.Context, metrics StoreMetrics
 		MinFlushUtilizationFraction.Get(&io.settings.SV),
 	)
 	io.adjustTokensResult = res
+	if rate := metrics.IOTokenRate; rate > 0 {
+		// The configured rate bounds the tokens computed from the LSM state.
+		limit := rate * adjustmentInterval
+		io.totalNumByteTokens = min(io.totalNumByteTokens, limit)
+		io.totalNumElasticByteTokens = min(io.totalNumElasticByteTokens, limit)
+	}
 	cumLSMIncomingBytes, cumLSMIngestedBytes := cumLSMWriteAndIngestedBytes(metrics.Metrics)
 	{
 		// Disk Bandwidth tokens.
@@ -393,6 +393,34 @@ func TestBadIOLoadListenerStats(t *testing.T) {
 	}
 }
 
+func TestIOLoadListenerIOTokenRate(t *testing.T) {
+	var m pebble.Metrics
+	req := &testRequesterForIOLL{}
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	ioll := ioLoadListener{
+		settings:              st,
+		kvRequester:           req,
+		perWorkTokenEstimator: makeStorePerWorkTokenEstimator(),
+		diskBandwidthLimiter:  makeDiskBandwidthLimiter(),
+		l0CompactedBytes:      metric.NewCounter(l0CompactedBytes),
+		l0TokensProduced:      metric.NewCounter(l0TokensProduced),
+	}
+	ioll.kvGranter = &testGranterNonNegativeTokens{t: t}
+
+	// The first tick initializes the stats, and the second computes tokens
+	// for an LSM which is not overloaded, which are unlimited.
+	ioll.pebbleMetricsTick(ctx, StoreMetrics{Metrics: &m})
+	ioll.pebbleMetricsTick(ctx, StoreMetrics{Metrics: &m})
+	require.Equal(t, int64(unlimitedTokens), ioll.totalNumByteTokens)
+
+	// The configured rate bounds the tokens given out over the interval.
+	const rate = 1 << 20
+	ioll.pebbleMetricsTick(ctx, StoreMetrics{Metrics: &m, IOTokenRate: rate})
+	require.Equal(t, int64(rate*adjustmentInterval), ioll.totalNumByteTokens)
+	require.Equal(t, int64(rate*adjustmentInterval), ioll.totalNumElasticByteTokens)
+}
+
 type testRequesterForIOLL struct {
 	stats storeAdmissionStats
 	buf   strings.Builder
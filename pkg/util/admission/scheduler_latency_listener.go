@@ -168,11 +168,11 @@ func (e *schedulerLatencyListener) getParams(period time.Duration) schedulerLate
 
 	enabled := elasticCPUControlEnabled.Get(&e.settings.SV)
 	targetP99 := elasticCPUSchedulerLatencyTarget.Get(&e.settings.SV)
-	minUtilization := elasticCPUMinUtilization.Get(&e.settings.SV)
-	maxUtilization := elasticCPUMaxUtilization.Get(&e.settings.SV)
+	minUtilization := ElasticCPUMinUtilization.Get(&e.settings.SV)
+	maxUtilization := ElasticCPUMaxUtilization.Get(&e.settings.SV)
 	if minUtilization > maxUtilization { // user error
-		defaultMinUtilization := elasticCPUMinUtilization.Default()
-		defaultMaxUtilization := elasticCPUMaxUtilization.Default()
+		defaultMinUtilization := ElasticCPUMinUtilization.Default()
+		defaultMaxUtilization := ElasticCPUMaxUtilization.Default()
 		log.Errorf(e.ctx, "min utilization (%0.2f%%) > max utilization (%0.2f%%); resetting to defaults [%0.2f%%, %0.2f%%]",
 			minUtilization*100, maxUtilization*100, defaultMinUtilization*100, defaultMaxUtilization*100,
 		)
@@ -207,7 +207,8 @@ type schedulerLatencyListenerParams struct {
 }
 
 var ( // cluster settings to control how elastic CPU % is adjusted
-	elasticCPUMaxUtilization = settings.RegisterFloatSetting(
+	// ElasticCPUMaxUtilization is the ceiling on elastic work CPU %.
+	ElasticCPUMaxUtilization = settings.RegisterFloatSetting(
 		settings.SystemOnly,
 		"admission.elastic_cpu.max_utilization",
 		"sets the ceiling on per-node elastic work CPU % utilization",
@@ -215,7 +216,8 @@ var ( // cluster settings to control how elastic CPU % is adjusted
 		settings.FloatInRange(0.05, 1.0),
 	)
 
-	elasticCPUMinUtilization = settings.RegisterFloatSetting(
+	// ElasticCPUMinUtilization is the floor on elastic work CPU %.
+	ElasticCPUMinUtilization = settings.RegisterFloatSetting(
 		settings.SystemOnly,
 		"admission.elastic_cpu.min_utilization",
 		"sets the floor on per-node elastic work CPU % utilization",
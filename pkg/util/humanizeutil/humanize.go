@@ -14,6 +14,9 @@ import (
 	"flag"
 	"fmt"
 	"math"
+	"regexp"
+	"strconv"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -123,6 +126,55 @@ func (b *BytesValue) IsSet() bool {
 	return b.isSet
 }
 
+// ByteSize is a size specified either as an absolute number of bytes, in any
+// format accepted by ParseBytes (e.g. 1024, 64MiB or .5GB), or relative to
+// some reference size, as a percentage (e.g. 25%) or as a decimal fraction
+// below 1 (e.g. .25 or 0.25). It is the result of ParseByteSize, which is
+// shared by size-valued flags and options so that they accept a single syntax.
+type ByteSize struct {
+	// Bytes is the absolute size. Only meaningful if IsPercent is false.
+	Bytes int64
+	// Percent is the size as a percentage of the reference size. Only
+	// meaningful if IsPercent is true.
+	Percent float64
+	// IsPercent is true if the size is relative to a reference size.
+	IsPercent bool
+}
+
+// byteSizeFractionRE recognizes sizes expressed as a decimal fraction of the
+// reference size.
+var byteSizeFractionRE = regexp.MustCompile(`^-?0*\.[0-9]+$`)
+
+// byteSizeDecimalRE recognizes decimals without a unit. Those which are not
+// fractions below 1 are rejected, rather than truncated to a byte count.
+var byteSizeDecimalRE = regexp.MustCompile(`^-?[0-9]*\.[0-9]*$`)
+
+// ParseByteSize parses a ByteSize. No range checks are performed; sizes can be
+// negative and percentages can exceed 100%.
+func ParseByteSize(s string) (ByteSize, error) {
+	if strings.HasSuffix(s, "%") || byteSizeFractionRE.MatchString(s) {
+		multiplier := 100.0
+		if strings.HasSuffix(s, "%") {
+			multiplier = 1.0
+			s = s[:len(s)-1]
+		}
+		frac, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return ByteSize{}, err
+		}
+		return ByteSize{Percent: frac * multiplier, IsPercent: true}, nil
+	}
+	if byteSizeDecimalRE.MatchString(s) {
+		return ByteSize{}, errors.Newf(
+			"invalid size %q: a decimal without a unit must be a fraction below 1", s)
+	}
+	v, err := ParseBytes(s)
+	if err != nil {
+		return ByteSize{}, err
+	}
+	return ByteSize{Bytes: v}, nil
+}
+
 // DataRate formats the passed byte count over duration as "x MiB/s".
 func DataRate(bytes int64, elapsed time.Duration) redact.SafeString {
 	if bytes == 0 {
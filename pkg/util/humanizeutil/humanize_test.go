@@ -102,3 +102,56 @@ func TestBytes(t *testing.T) {
 		}
 	}
 }
+
+func TestParseByteSize(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	testCases := []struct {
+		value  string
+		exp    humanizeutil.ByteSize
+		expErr string
+	}{
+		// Integers.
+		{value: "0", exp: humanizeutil.ByteSize{}},
+		{value: "1024", exp: humanizeutil.ByteSize{Bytes: 1024}},
+		{value: "-1024", exp: humanizeutil.ByteSize{Bytes: -1024}},
+		// Human suffixes.
+		{value: "64MiB", exp: humanizeutil.ByteSize{Bytes: 64 << 20}},
+		{value: "20GB", exp: humanizeutil.ByteSize{Bytes: 20000000000}},
+		{value: ".5GiB", exp: humanizeutil.ByteSize{Bytes: 512 << 20}},
+		{value: "1.5KiB", exp: humanizeutil.ByteSize{Bytes: 1536}},
+		// Percentages and fractions.
+		{value: "25%", exp: humanizeutil.ByteSize{Percent: 25, IsPercent: true}},
+		{value: "50.5%", exp: humanizeutil.ByteSize{Percent: 50.5, IsPercent: true}},
+		{value: "0%", exp: humanizeutil.ByteSize{IsPercent: true}},
+		{value: ".25", exp: humanizeutil.ByteSize{Percent: 25, IsPercent: true}},
+		{value: "0.5", exp: humanizeutil.ByteSize{Percent: 50, IsPercent: true}},
+		// Errors.
+		{value: "", expErr: `parsing "": invalid syntax`},
+		{value: "abc", expErr: `strconv.ParseFloat: parsing "": invalid syntax`},
+		{value: "1 ZB", expErr: "unhandled size name: zb"},
+		{value: "abc%", expErr: `strconv.ParseFloat: parsing "abc": invalid syntax`},
+		{value: "%", expErr: `strconv.ParseFloat: parsing "": invalid syntax`},
+		// Decimals of 1 or more need a unit.
+		{value: "1.3", expErr: `invalid size "1.3": a decimal without a unit must be a fraction below 1`},
+		{value: "1.0", expErr: `invalid size "1.0": a decimal without a unit must be a fraction below 1`},
+		{value: "2.", expErr: `invalid size "2.": a decimal without a unit must be a fraction below 1`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			b, err := humanizeutil.ParseByteSize(tc.value)
+			if tc.expErr != "" {
+				if err == nil || err.Error() != tc.expErr {
+					t.Fatalf("expected error %q, got %v", tc.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b != tc.exp {
+				t.Fatalf("expected %+v, got %+v", tc.exp, b)
+			}
+		})
+	}
+}
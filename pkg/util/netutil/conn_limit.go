@@ -0,0 +1,166 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package netutil
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/errors"
+	"golang.org/x/time/rate"
+)
+
+// LimitConnsPerIP wraps a listener so that at most maxConns connections
+// originating from the same client IP address are open at any given time.
+// Connections accepted beyond that limit are closed immediately and not
+// returned to the caller. Connections whose remote address does not carry an
+// IP (e.g. unix sockets) are not limited. A maxConns of zero or less disables
+// the limit and returns the listener unchanged. Rejections are logged with
+// ctx.
+//
+// The accepted connections are wrapped; their NetConn method returns the
+// connection accepted from l.
+func LimitConnsPerIP(ctx context.Context, l net.Listener, maxConns int) net.Listener {
+	if maxConns <= 0 {
+		return l
+	}
+	pl := &perIPLimitListener{Listener: l, ctx: ctx, maxConns: maxConns}
+	pl.mu.conns = make(map[string]int)
+	return pl
+}
+
+type perIPLimitListener struct {
+	net.Listener
+	ctx      context.Context
+	maxConns int
+
+	mu struct {
+		syncutil.Mutex
+		// conns is the number of open connections per client IP.
+		conns map[string]int
+	}
+}
+
+// Accept implements the net.Listener interface.
+func (l *perIPLimitListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
+		if err != nil || host == "" {
+			return conn, nil
+		}
+		if !l.acquire(host) {
+			log.Ops.Warningf(l.ctx,
+				"rejecting connection from %s: more than %d connections open from this address",
+				conn.RemoteAddr(), l.maxConns)
+			_ = conn.Close()
+			continue
+		}
+		return &perIPLimitConn{Conn: conn, release: func() { l.release(host) }}, nil
+	}
+}
+
+func (l *perIPLimitListener) acquire(host string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.mu.conns[host] >= l.maxConns {
+		return false
+	}
+	l.mu.conns[host]++
+	return true
+}
+
+func (l *perIPLimitListener) release(host string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.mu.conns[host]--; l.mu.conns[host] <= 0 {
+		delete(l.mu.conns, host)
+	}
+}
+
+// perIPLimitConn releases its slot in the perIPLimitListener when closed.
+type perIPLimitConn struct {
+	net.Conn
+	releaseOnce sync.Once
+	release     func()
+}
+
+// NetConn returns the connection wrapped by c, like tls.Conn.NetConn.
+func (c *perIPLimitConn) NetConn() net.Conn {
+	return c.Conn
+}
+
+// Close implements the net.Conn interface.
+func (c *perIPLimitConn) Close() error {
+	err := c.Conn.Close()
+	c.releaseOnce.Do(c.release)
+	return err
+}
+
+// LimitConnRate wraps a listener so that it hands out new connections at a
+// rate of at most connsPerSecond, allowing bursts of up to connsPerSecond
+// connections. Connections beyond the rate are delayed in Accept until the
+// token bucket refills; clients queue up in the listen backlog in the
+// meantime. A delayed Accept returns when the listener is closed or the
+// stopper quiesces. A connsPerSecond of zero or less disables the limit and
+// returns the listener unchanged.
+func LimitConnRate(
+	ctx context.Context, stopper *stop.Stopper, l net.Listener, connsPerSecond int,
+) net.Listener {
+	if connsPerSecond <= 0 {
+		return l
+	}
+	return newRateLimitListener(ctx, stopper, l,
+		rate.NewLimiter(rate.Limit(connsPerSecond), connsPerSecond))
+}
+
+func newRateLimitListener(
+	ctx context.Context, stopper *stop.Stopper, l net.Listener, limiter *rate.Limiter,
+) *rateLimitListener {
+	ctx, cancel := stopper.WithCancelOnQuiesce(ctx)
+	return &rateLimitListener{Listener: l, ctx: ctx, cancel: cancel, limiter: limiter}
+}
+
+type rateLimitListener struct {
+	net.Listener
+	// ctx is canceled when the listener is closed or the stopper quiesces.
+	ctx     context.Context
+	cancel  func()
+	limiter *rate.Limiter
+}
+
+// Accept implements the net.Listener interface.
+func (l *rateLimitListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	if err := l.limiter.Wait(l.ctx); err != nil {
+		_ = conn.Close()
+		// Report the listener as closed, which is what callers of Accept
+		// expect when shutting down.
+		return nil, errors.Mark(err, net.ErrClosed)
+	}
+	return conn, nil
+}
+
+// Close implements the net.Listener interface.
+func (l *rateLimitListener) Close() error {
+	l.cancel()
+	return l.Listener.Close()
+}
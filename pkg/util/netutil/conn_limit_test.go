@@ -0,0 +1,177 @@
+// Copyright 2024 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package netutil
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestLimitConnsPerIP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = ln.Close() }()
+
+	const maxConns = 2
+	limited := LimitConnsPerIP(context.Background(), ln, maxConns)
+	accepted := make(chan net.Conn)
+	go func() {
+		for {
+			conn, err := limited.Accept()
+			if err != nil {
+				close(accepted)
+				return
+			}
+			accepted <- conn
+		}
+	}()
+
+	dial := func() net.Conn {
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		require.NoError(t, err)
+		return conn
+	}
+	// expectRejected asserts that the server closed the connection without
+	// handing it to the caller of Accept.
+	expectRejected := func(conn net.Conn) {
+		require.NoError(t, conn.SetReadDeadline(timeutil.Now().Add(10*time.Second)))
+		_, err := conn.Read(make([]byte, 1))
+		require.ErrorIs(t, err, io.EOF)
+		_ = conn.Close()
+	}
+
+	// The first maxConns connections from 127.0.0.1 are accepted.
+	var serverConns []net.Conn
+	for i := 0; i < maxConns; i++ {
+		client := dial()
+		defer func() { _ = client.Close() }()
+		serverConns = append(serverConns, <-accepted)
+	}
+	// The accepted connections expose the connection they wrap.
+	require.IsType(t, &net.TCPConn{}, serverConns[0].(interface{ NetConn() net.Conn }).NetConn())
+
+	// Further connections from the same address are rejected.
+	expectRejected(dial())
+	expectRejected(dial())
+
+	// Closing an accepted connection frees up a slot.
+	require.NoError(t, serverConns[0].Close())
+	client := dial()
+	defer func() { _ = client.Close() }()
+	serverConns = append(serverConns[1:], <-accepted)
+
+	expectRejected(dial())
+
+	for _, conn := range serverConns {
+		require.NoError(t, conn.Close())
+	}
+}
+
+func TestLimitConnsPerIPDisabled(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = ln.Close() }()
+
+	require.Equal(t, ln, LimitConnsPerIP(context.Background(), ln, 0))
+}
+
+func TestLimitConnRate(t *testing.T) {
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	const connsPerSecond = 5
+	limited := LimitConnRate(ctx, stopper, ln, connsPerSecond).(*rateLimitListener)
+	require.Equal(t, rate.Limit(connsPerSecond), limited.limiter.Limit())
+	require.Equal(t, connsPerSecond, limited.limiter.Burst())
+
+	// Use a limiter which does not refill during the test, so that the
+	// connections beyond the burst are held back deterministically.
+	const burst = 2
+	limited = newRateLimitListener(ctx, stopper, ln, rate.NewLimiter(rate.Every(time.Hour), burst))
+	accepted := make(chan net.Conn)
+	acceptErr := make(chan error, 1)
+	go func() {
+		for {
+			conn, err := limited.Accept()
+			if err != nil {
+				acceptErr <- err
+				return
+			}
+			accepted <- conn
+		}
+	}()
+
+	for i := 0; i < burst+1; i++ {
+		client, err := net.Dial("tcp", ln.Addr().String())
+		require.NoError(t, err)
+		defer func() { _ = client.Close() }()
+	}
+	for i := 0; i < burst; i++ {
+		require.NoError(t, (<-accepted).Close())
+	}
+	// The burst used up the tokens, and the last connection waits for one.
+	require.Less(t, limited.limiter.Tokens(), 1.0)
+
+	// Closing the listener releases the waiting Accept.
+	require.NoError(t, limited.Close())
+	err = <-acceptErr
+	require.True(t, IsClosedConnection(err), "%+v", err)
+}
+
+func TestLimitConnRateStopperQuiesce(t *testing.T) {
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = ln.Close() }()
+
+	// No tokens are available, so the first connection waits.
+	limited := newRateLimitListener(ctx, stopper, ln, rate.NewLimiter(rate.Every(time.Hour), 1))
+	limited.limiter.Allow()
+	acceptErr := make(chan error, 1)
+	go func() {
+		_, err := limited.Accept()
+		acceptErr <- err
+	}()
+	client, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer func() { _ = client.Close() }()
+
+	// Quiescing the stopper releases the waiting Accept.
+	stopper.Quiesce(ctx)
+	err = <-acceptErr
+	require.True(t, IsClosedConnection(err), "%+v", err)
+}
+
+func TestLimitConnRateDisabled(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = ln.Close() }()
+
+	ctx := context.Background()
+	stopper := stop.NewStopper()
+	defer stopper.Stop(ctx)
+	require.Equal(t, ln, LimitConnRate(ctx, stopper, ln, 0))
+}